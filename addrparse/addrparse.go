@@ -0,0 +1,349 @@
+// Package addrparse splits free-text Spanish/Bolivian addresses into their
+// components and writes them back in a single canonical form, so that
+// "Av. Banzer 3er anillo" and "avenida banzer tercer anillo" are the same address
+package addrparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"maps.patio.com/entity"
+)
+
+const (
+	fieldStreet = iota
+	fieldNumber
+	fieldCorner
+	fieldBetween
+	fieldRadial
+	fieldNeighbourhood
+	fieldUV
+	fieldBlock
+	fieldLot
+)
+
+var streetTypes = map[string]string{
+	"av":           "avenida",
+	"avda":         "avenida",
+	"avenida":      "avenida",
+	"c":            "calle",
+	"cl":           "calle",
+	"calle":        "calle",
+	"pje":          "pasaje",
+	"psje":         "pasaje",
+	"pasaje":       "pasaje",
+	"carr":         "carretera",
+	"carretera":    "carretera",
+	"cam":          "camino",
+	"camino":       "camino",
+	"prolongacion": "prolongacion",
+	"prol":         "prolongacion",
+}
+
+var keywords = map[string]int{
+	"nro":          fieldNumber,
+	"no":           fieldNumber,
+	"num":          fieldNumber,
+	"numero":       fieldNumber,
+	"n":            fieldNumber,
+	"esq":          fieldCorner,
+	"esquina":      fieldCorner,
+	"entre":        fieldBetween,
+	"radial":       fieldRadial,
+	"rad":          fieldRadial,
+	"b":            fieldNeighbourhood,
+	"bo":           fieldNeighbourhood,
+	"barrio":       fieldNeighbourhood,
+	"urb":          fieldNeighbourhood,
+	"urbanizacion": fieldNeighbourhood,
+	"uv":           fieldUV,
+	"mz":           fieldBlock,
+	"mzn":          fieldBlock,
+	"mzno":         fieldBlock,
+	"manzano":      fieldBlock,
+	"manzana":      fieldBlock,
+	"lt":           fieldLot,
+	"lote":         fieldLot,
+}
+
+var ordinals = map[string]int{
+	"primer": 1, "primero": 1, "primera": 1,
+	"segundo": 2, "segunda": 2,
+	"tercer": 3, "tercero": 3, "tercera": 3,
+	"cuarto": 4, "cuarta": 4,
+	"quinto": 5, "quinta": 5,
+	"sexto": 6, "sexta": 6,
+	"septimo": 7, "septima": 7,
+	"octavo": 8, "octava": 8,
+	"noveno": 9, "novena": 9,
+	"decimo": 10, "decima": 10,
+}
+
+// months name streets and squares after dates, "plaza 24 de septiembre"
+var months = map[string]bool{
+	"enero": true, "febrero": true, "marzo": true, "abril": true, "mayo": true, "junio": true, "julio": true,
+	"agosto": true, "septiembre": true, "setiembre": true, "octubre": true, "noviembre": true, "diciembre": true,
+}
+
+// cities maps the usual spellings and abbreviations to the city name
+var cities = map[string]string{
+	"santa cruz de la sierra": "santa cruz de la sierra",
+	"santa cruz":              "santa cruz de la sierra",
+	"scz":                     "santa cruz de la sierra",
+	"la paz":                  "la paz",
+	"lpz":                     "la paz",
+	"el alto":                 "el alto",
+	"cochabamba":              "cochabamba",
+	"cbba":                    "cochabamba",
+	"sucre":                   "sucre",
+	"tarija":                  "tarija",
+	"oruro":                   "oruro",
+	"potosi":                  "potosi",
+	"trinidad":                "trinidad",
+	"cobija":                  "cobija",
+	"montero":                 "montero",
+	"warnes":                  "warnes",
+	"bolivia":                 "",
+}
+
+var (
+	ordinalNumber = regexp.MustCompile(`^(\d{1,2})(?:er|ro|ra|do|da|to|ta|mo|ma|vo|va|no|na|o|a)?$`)
+	numeric       = regexp.MustCompile(`^\d+[a-z]?$`)
+	separators    = regexp.MustCompile(`[.,;:#°º/\-()]`)
+)
+
+var accents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u")
+
+// Normalize lowercases the text, removes accents and punctuation and collapses spaces
+func Normalize(text string) string {
+	text = accents.Replace(strings.ToLower(text))
+	// "b/" and "c/" are common abbreviations for barrio and calle
+	text = separators.ReplaceAllString(text, " $0 ")
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Parse returns the components found in the address, tokens that are not
+// recognised are kept in the street name so no information is lost
+func Parse(text string) *entity.ParsedAddress {
+	parsed := &entity.ParsedAddress{}
+
+	segments := strings.Split(Normalize(text), ",")
+	if city, rest := findCity(segments); city != "" || len(rest) != len(segments) {
+		parsed.City = city
+		segments = rest
+	}
+
+	for _, segment := range segments {
+		parseSegment(segment, parsed)
+	}
+	return parsed
+}
+
+// findCity looks for a known city name at the end of the address
+func findCity(segments []string) (string, []string) {
+	for len(segments) > 0 {
+		last := strings.TrimSpace(clean(segments[len(segments)-1]))
+		city, ok := cities[last]
+		if ok {
+			segments = segments[:len(segments)-1]
+			if city != "" {
+				return city, segments
+			}
+			continue
+		}
+		for name, value := range cities {
+			if value != "" && strings.HasSuffix(last, " "+name) {
+				segments[len(segments)-1] = strings.TrimSuffix(last, " "+name)
+				return value, segments
+			}
+		}
+		break
+	}
+	return "", segments
+}
+
+func parseSegment(segment string, parsed *entity.ParsedAddress) {
+	tokens := strings.Fields(clean(segment))
+	field := fieldStreet
+	if parsed.Street != "" {
+		field = -1
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		// a date is part of the name, not a door number followed by a barrio
+		if ordinalNumber.MatchString(token) && i+2 < len(tokens) && tokens[i+1] == "de" && months[tokens[i+2]] {
+			token = strings.Join(tokens[i:i+3], " ")
+			i += 2
+		}
+		if _, keyword := keywords[token]; field == fieldBetween && !keyword {
+			if value, ok := ordinal(token); ok {
+				token = strconv.Itoa(value)
+			}
+			appendField(parsed, field, token)
+			continue
+		}
+		if ring, ok := ordinal(token); ok && i+1 < len(tokens) && tokens[i+1] == "anillo" {
+			parsed.Ring = ring
+			i++
+			field = -1
+			continue
+		}
+		if token == "anillo" {
+			if i+1 < len(tokens) {
+				if ring, ok := ordinal(tokens[i+1]); ok {
+					parsed.Ring = ring
+					i++
+				}
+			}
+			field = -1
+			continue
+		}
+		if streetType, ok := streetTypes[token]; ok && parsed.Street == "" && (field == fieldStreet || field == -1) {
+			parsed.StreetType = streetType
+			field = fieldStreet
+			continue
+		}
+		if next, ok := keywords[token]; ok && i+1 < len(tokens) {
+			field = next
+			continue
+		}
+
+		if field == fieldStreet && parsed.Street != "" && numeric.MatchString(token) && parsed.Number == "" {
+			parsed.Number = token
+			field = -1
+			continue
+		}
+		if field == -1 {
+			if parsed.Street == "" {
+				field = fieldStreet
+			} else if parsed.Neighbourhood == "" {
+				field = fieldNeighbourhood
+			} else {
+				field = fieldStreet
+			}
+		}
+		appendField(parsed, field, token)
+		if field == fieldNumber || field == fieldUV || field == fieldBlock || field == fieldLot || field == fieldRadial {
+			field = -1
+		}
+	}
+}
+
+func appendField(parsed *entity.ParsedAddress, field int, token string) {
+	var target *string
+	switch field {
+	case fieldStreet:
+		target = &parsed.Street
+	case fieldNumber:
+		target = &parsed.Number
+	case fieldCorner:
+		target = &parsed.Corner
+	case fieldBetween:
+		target = &parsed.Between
+	case fieldRadial:
+		target = &parsed.Radial
+	case fieldNeighbourhood:
+		target = &parsed.Neighbourhood
+	case fieldUV:
+		target = &parsed.UV
+	case fieldBlock:
+		target = &parsed.Block
+	case fieldLot:
+		target = &parsed.Lot
+	default:
+		return
+	}
+	if *target != "" {
+		*target += " "
+	}
+	*target += token
+}
+
+// clean removes the punctuation left by Normalize
+func clean(segment string) string {
+	return strings.Join(strings.Fields(separators.ReplaceAllString(segment, " ")), " ")
+}
+
+func ordinal(token string) (int, bool) {
+	if value, ok := ordinals[token]; ok {
+		return value, true
+	}
+	if match := ordinalNumber.FindStringSubmatch(token); match != nil {
+		value, _ := strconv.Atoi(match[1])
+		return value, value > 0 && value <= 12
+	}
+	return 0, false
+}
+
+var ordinalSuffixes = []string{"", "er", "do", "er", "to", "to", "to", "mo", "vo", "no", "mo", "mo", "mo"}
+
+// ordinalText writes the ring the way it is written on street signs, 3er, 4to...
+func ordinalText(value int) string {
+	if value < len(ordinalSuffixes) {
+		return fmt.Sprintf("%d%s", value, ordinalSuffixes[value])
+	}
+	return strconv.Itoa(value)
+}
+
+// Street returns the canonical form without the city
+func Street(parsed *entity.ParsedAddress) string {
+	parts := []string{}
+
+	street := strings.TrimSpace(parsed.StreetType + " " + parsed.Street)
+	if parsed.Number != "" {
+		street += " " + parsed.Number
+	}
+	if street != "" {
+		parts = append(parts, street)
+	}
+	if parsed.Corner != "" {
+		parts = append(parts, "esquina "+parsed.Corner)
+	}
+	if parsed.Between != "" {
+		parts = append(parts, "entre "+parsed.Between)
+	}
+	if parsed.Ring > 0 {
+		parts = append(parts, ordinalText(parsed.Ring)+" anillo")
+	}
+	if parsed.Radial != "" {
+		parts = append(parts, "radial "+parsed.Radial)
+	}
+	if parsed.Neighbourhood != "" {
+		parts = append(parts, "barrio "+parsed.Neighbourhood)
+	}
+	if parsed.UV != "" {
+		parts = append(parts, "uv "+parsed.UV)
+	}
+	if parsed.Block != "" {
+		parts = append(parts, "manzano "+parsed.Block)
+	}
+	if parsed.Lot != "" {
+		parts = append(parts, "lote "+parsed.Lot)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Canonical returns the single form shared by every spelling of the address
+func Canonical(parsed *entity.ParsedAddress) string {
+	street := Street(parsed)
+	if parsed.City == "" {
+		return street
+	}
+	if street == "" {
+		return parsed.City
+	}
+	return street + ", " + parsed.City
+}
+
+// Key returns the canonical form of the raw text
+func Key(text string) string {
+	return Canonical(Parse(text))
+}
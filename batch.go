@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"maps.patio.com/compare"
+	"maps.patio.com/entity"
+)
+
+// batchResult is a row of the batch output, Address is nil when the lookup failed
+type batchResult struct {
+	Line    int             `json:"line"`
+	Input   string          `json:"input"`
+	Status  string          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Address *entity.Address `json:"address"`
+}
+
+// batch geocodes every address, and reverse geocodes every lat,lng pair, of
+// a CSV, failed rows are kept in the output with their error
+func batch(args []string) {
+	flags := newLookupFlags("batch", "-in file.csv [-out file.csv] [flags]", FORMAT_CSV)
+	in := flags.String("in", "", "CSV with an address or a lat,lng pair per row, - reads stdin")
+	out := flags.String("out", "", "output file, stdout when empty")
+	flags.parse(args)
+
+	if *in == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	input := os.Stdin
+	if *in != "-" {
+		var err error
+		input, err = os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer input.Close()
+	}
+	inputs, err := compare.ReadCSV(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, repo := newRepository(flags.language)
+
+	results := []*batchResult{}
+	rows := &table{Header: append([]string{"line", "input", "status", "error"}, addressHeader...)}
+	failed := 0
+	for _, v := range inputs {
+		result := &batchResult{Line: v.Line, Input: v.String()}
+		if v.Location != nil {
+			result.Status, result.Address, err = repo.ReverseGeocoding(v.Location, *flags.language)
+		} else {
+			result.Status, result.Address, err = repo.Geocoding(v.Address, *flags.language)
+		}
+
+		row := []string{strconv.Itoa(result.Line), result.Input, result.Status, "", "", "", "", "", ""}
+		if err != nil {
+			failed++
+			result.Error = err.Error()
+			result.Address = nil
+			row[3] = result.Error
+		} else {
+			copy(row[4:], addressRow(result.Address))
+		}
+		results = append(results, result)
+		rows.Rows = append(rows.Rows, row)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		w, err = os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer w.Close()
+	}
+	if err := (&output{Value: results, Tables: []*table{rows}}).write(w, *flags.format); err != nil {
+		log.Fatal(err)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d rows failed\n", failed, len(inputs))
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type item struct {
+	value   interface{}
+	expires time.Time
+}
+
+// Cache is an in-memory key/value store with expiration, safe for concurrent use
+type Cache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	size  int
+	items map[string]item
+}
+
+// New returns a cache keeping entries for ttl, at most size entries when size is positive
+func New(ttl time.Duration, size int) *Cache {
+	return &Cache{
+		ttl:   ttl,
+		size:  size,
+		items: map[string]item{},
+	}
+}
+
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(v.expires) {
+		delete(c.items, key)
+		return nil, false
+	}
+	return v.value, true
+}
+
+func (c *Cache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.size > 0 && len(c.items) >= c.size {
+		c.evict(now)
+	}
+	c.items[key] = item{
+		value:   value,
+		expires: now.Add(c.ttl),
+	}
+}
+
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
+
+// evict drops expired entries, or the one closest to expire when none are
+func (c *Cache) evict(now time.Time) {
+	var oldest string
+	var oldestExpires time.Time
+	for k, v := range c.items {
+		if now.After(v.expires) {
+			delete(c.items, k)
+			continue
+		}
+		if oldest == "" || v.expires.Before(oldestExpires) {
+			oldest = k
+			oldestExpires = v.expires
+		}
+	}
+	if len(c.items) >= c.size {
+		delete(c.items, oldest)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"maps.patio.com/compare"
+	"maps.patio.com/configuration"
+	"maps.patio.com/repository"
+)
+
+// compareProviders runs the addresses of a CSV through maps and every
+// consensus provider and prints how far their answers are from each other
+func compareProviders(args []string) {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	in := flags.String("in", "", "CSV with an address or a lat,lng pair per row, - reads stdin")
+	out := flags.String("out", "", "optional CSV with the answer of every provider per row")
+	language := flags.String("language", "", "result language, defaults to maps.language")
+	flags.Parse(args)
+
+	if *in == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, err := configuration.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *language == "" {
+		*language = config.MAPS.Language
+	}
+
+	providers, err := compareList(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	input := os.Stdin
+	if *in != "-" {
+		input, err = os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer input.Close()
+	}
+	inputs, err := compare.ReadCSV(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := compare.Run(providers, inputs, *language)
+
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		if err := report.WriteCSV(file); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// compareList returns the maps provider first, it is the baseline of the deltas,
+// the cache and the manual overrides are left out on purpose so every lookup
+// reaches the provider and pinned addresses do not hide its answers
+func compareList(config *configuration.Configuration) ([]*compare.Provider, error) {
+	uncached := *config
+	uncached.CACHE.TTL = 0
+
+	configured := append([]configuration.Maps{config.MAPS}, config.CONSENSUS.Providers...)
+	providers := []*compare.Provider{}
+	seen := map[string]int{}
+	for _, maps := range configured {
+		key := maps.Provider + ":" + maps.ApiKey
+		if seen[key] > 0 {
+			continue
+		}
+		seen[key]++
+
+		repo, err := repository.NewProvider(&uncached, maps)
+		if err != nil {
+			return nil, err
+		}
+		if o, ok := repo.(*repository.Overrides); ok {
+			repo = o.Repository
+		}
+
+		name := maps.Provider
+		if seen[name] > 0 {
+			name = fmt.Sprintf("%s#%d", name, seen[name]+1)
+		}
+		seen[maps.Provider]++
+
+		cost, ok := config.COMPARE.Costs[maps.Provider]
+		if !ok {
+			cost = compare.DEFAULT_COSTS[maps.Provider]
+		}
+		providers = append(providers, &compare.Provider{Name: name, Repository: repo, Cost: cost})
+	}
+	return providers, nil
+}
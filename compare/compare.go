@@ -0,0 +1,269 @@
+package compare
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	"maps.patio.com/repository"
+)
+
+// DEFAULT_COSTS are the list prices in USD per 1000 geocoding requests
+var DEFAULT_COSTS = map[string]float64{
+	"google_maps": 5,
+	"here_maps":   0.83,
+}
+
+// Input is a row of the CSV, Location is only set for coordinate pairs
+type Input struct {
+	Line     int
+	Address  string
+	Location *entity.Location
+}
+
+func (i *Input) String() string {
+	if i.Location != nil {
+		return fmt.Sprintf("%v,%v", i.Location.Lat, i.Location.Lng)
+	}
+	return i.Address
+}
+
+// Provider is a repository under comparison, Cost is in USD per 1000 requests
+type Provider struct {
+	Name       string
+	Repository repository.Repository
+	Cost       float64
+}
+
+// Result is the answer of a provider for an input, Delta is the distance in
+// meters to the answer of the first provider and only valid when Compared
+type Result struct {
+	Input    *Input
+	Provider string
+	Status   string
+	Error    string
+	Address  *entity.Address
+	Latency  time.Duration
+	Delta    float64
+	Compared bool
+}
+
+type Summary struct {
+	Provider  string
+	Requests  int
+	Errors    int
+	ErrorRate float64
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	Compared  int
+	MeanDelta float64
+	P50Delta  float64
+	P90Delta  float64
+	MaxDelta  float64
+	Cost      float64
+}
+
+type Report struct {
+	Results   []*Result
+	Summaries []*Summary
+}
+
+// ReadCSV reads one address per row, or a lat,lng pair for reverse geocoding,
+// a first row naming the columns is skipped
+func ReadCSV(r io.Reader) ([]*Input, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	inputs := []*Input{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if line == 1 && isHeader(record[0]) {
+			continue
+		}
+
+		// an unquoted address with commas spans several columns
+		input := &Input{Line: line, Address: strings.TrimSpace(strings.Join(record, ", "))}
+		if len(record) >= 2 {
+			lat, errLat := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+			lng, errLng := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+			if errLat == nil && errLng == nil {
+				if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+					return nil, fmt.Errorf("line %d: coordinates out of range", line)
+				}
+				input.Address = ""
+				input.Location = &entity.Location{Lat: lat, Lng: lng}
+			}
+		}
+		inputs = append(inputs, input)
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("no addresses or coordinates found")
+	}
+	return inputs, nil
+}
+
+func isHeader(column string) bool {
+	switch strings.ToLower(strings.TrimSpace(column)) {
+	case "address", "lat", "latitude":
+		return true
+	}
+	return false
+}
+
+// Run looks up every input with every provider, one request at a time so
+// the latencies are not skewed by concurrent calls
+func Run(providers []*Provider, inputs []*Input, language string) *Report {
+	report := &Report{}
+	for _, input := range inputs {
+		var baseline *Result
+		for i, provider := range providers {
+			result := lookup(provider, input, language)
+			if i == 0 {
+				baseline = result
+			} else if baseline.Address != nil && baseline.Address.Location != nil && result.Address != nil && result.Address.Location != nil {
+				result.Delta = geometry.Haversine(baseline.Address.Location, result.Address.Location)
+				result.Compared = true
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	for i, provider := range providers {
+		report.Summaries = append(report.Summaries, summarize(provider, report.Results, i > 0))
+	}
+	return report
+}
+
+func lookup(provider *Provider, input *Input, language string) *Result {
+	var statusMaps string
+	var address *entity.Address
+	var err error
+
+	start := time.Now()
+	if input.Location != nil {
+		statusMaps, address, err = provider.Repository.ReverseGeocoding(input.Location, language)
+	} else {
+		statusMaps, address, err = provider.Repository.Geocoding(input.Address, language)
+	}
+	result := &Result{Input: input, Provider: provider.Name, Status: statusMaps, Latency: time.Since(start)}
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Address = address
+	}
+	return result
+}
+
+func summarize(provider *Provider, results []*Result, compared bool) *Summary {
+	summary := &Summary{Provider: provider.Name}
+	latencies := []time.Duration{}
+	deltas := []float64{}
+	for _, v := range results {
+		if v.Provider != provider.Name {
+			continue
+		}
+		summary.Requests++
+		if v.Error != "" {
+			summary.Errors++
+		}
+		latencies = append(latencies, v.Latency)
+		if compared && v.Compared {
+			deltas = append(deltas, v.Delta)
+		}
+	}
+	if summary.Requests == 0 {
+		return summary
+	}
+
+	summary.ErrorRate = float64(summary.Errors) / float64(summary.Requests)
+	summary.Cost = float64(summary.Requests) * provider.Cost / 1000
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	summary.P50 = latencies[rank(len(latencies), 50)]
+	summary.P90 = latencies[rank(len(latencies), 90)]
+	summary.P99 = latencies[rank(len(latencies), 99)]
+
+	summary.Compared = len(deltas)
+	if len(deltas) > 0 {
+		sort.Float64s(deltas)
+		for _, d := range deltas {
+			summary.MeanDelta += d / float64(len(deltas))
+		}
+		summary.P50Delta = deltas[rank(len(deltas), 50)]
+		summary.P90Delta = deltas[rank(len(deltas), 90)]
+		summary.MaxDelta = deltas[len(deltas)-1]
+	}
+	return summary
+}
+
+// rank is the nearest-rank index of a percentile in a sorted list of n values
+func rank(n int, percentile int) int {
+	index := (percentile*n+99)/100 - 1
+	if index < 0 {
+		return 0
+	}
+	return index
+}
+
+// WriteText writes the summary table, deltas are meters to the first provider
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "provider\trequests\terrors\terror rate\tp50\tp90\tp99\tcompared\tmean delta\tp50 delta\tp90 delta\tmax delta\tcost (USD)\t")
+	for i, s := range r.Summaries {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\t%s\t%s\t%s\t", s.Provider, s.Requests, s.Errors, s.ErrorRate*100,
+			milliseconds(s.P50), milliseconds(s.P90), milliseconds(s.P99))
+		if i == 0 {
+			fmt.Fprint(tw, "baseline\t-\t-\t-\t-\t")
+		} else {
+			fmt.Fprintf(tw, "%d\t%.0f m\t%.0f m\t%.0f m\t%.0f m\t", s.Compared, s.MeanDelta, s.P50Delta, s.P90Delta, s.MaxDelta)
+		}
+		fmt.Fprintf(tw, "%.3f\t\n", s.Cost)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes a row per input and provider
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"line", "input", "provider", "status", "error", "address", "lat", "lng", "latency_ms", "delta_m"})
+	for _, v := range r.Results {
+		row := []string{strconv.Itoa(v.Input.Line), v.Input.String(), v.Provider, v.Status, v.Error, "", "", "", "", ""}
+		if v.Address != nil {
+			row[5] = v.Address.Address
+			if v.Address.Location != nil {
+				row[6] = strconv.FormatFloat(v.Address.Location.Lat, 'f', -1, 64)
+				row[7] = strconv.FormatFloat(v.Address.Location.Lng, 'f', -1, 64)
+			}
+		}
+		row[8] = strconv.FormatInt(v.Latency.Milliseconds(), 10)
+		if v.Compared {
+			row[9] = strconv.FormatFloat(v.Delta, 'f', 1, 64)
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func milliseconds(d time.Duration) string {
+	return fmt.Sprintf("%d ms", d.Milliseconds())
+}
@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"io/ioutil"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -9,6 +10,69 @@ import (
 type Maps struct {
 	Provider string `yaml:"provider"`
 	ApiKey   string `yaml:"api_key"`
+	Language string `yaml:"language"`
+}
+
+// Match.MaxPoints caps the points of a trace, zero uses the default of 100
+type Match struct {
+	Provider  string `yaml:"provider"`
+	Url       string `yaml:"url"`
+	MaxPoints int    `yaml:"max_points"`
+}
+
+type Elevation struct {
+	Provider string `yaml:"provider"`
+	Path     string `yaml:"path"`
+}
+
+type TimeZone struct {
+	Path     string `yaml:"path"`
+	Fallback string `yaml:"fallback"`
+}
+
+// Consensus geocodes with every provider, answers farther apart than Threshold meters disagree
+type Consensus struct {
+	Threshold float64 `yaml:"threshold"`
+	Providers []Maps  `yaml:"providers"`
+}
+
+// Compare overrides the cost in USD per 1000 requests of a provider
+type Compare struct {
+	Costs map[string]float64 `yaml:"costs"`
+}
+
+type Cache struct {
+	TTL  time.Duration `yaml:"ttl"`
+	Size int           `yaml:"size"`
+}
+
+// Overrides.FeedbackToken is the bearer token of the app reporting wrong results
+type Overrides struct {
+	Path          string `yaml:"path"`
+	FeedbackToken string `yaml:"feedback_token"`
+}
+
+// SavedPlaces.Token is the bearer token of the backend calling the customer endpoints
+type SavedPlaces struct {
+	Path  string `yaml:"path"`
+	Token string `yaml:"token"`
+}
+
+type Admin struct {
+	Token string `yaml:"token"`
+}
+
+// Vehicle is a routing profile, dimensions in meters and weights in kilograms
+type Vehicle struct {
+	Mode           string   `yaml:"mode"`
+	Height         float64  `yaml:"height"`
+	Width          float64  `yaml:"width"`
+	Length         float64  `yaml:"length"`
+	GrossWeight    float64  `yaml:"gross_weight"`
+	WeightPerAxle  float64  `yaml:"weight_per_axle"`
+	AxleCount      int      `yaml:"axle_count"`
+	TrailerCount   int      `yaml:"trailer_count"`
+	HazardousGoods []string `yaml:"hazardous_goods"`
 }
 
 type App struct {
@@ -17,8 +81,18 @@ type App struct {
 }
 
 type Configuration struct {
-	MAPS Maps `yaml:"maps"`
-	APP  App  `yaml:"app"`
+	MAPS         Maps               `yaml:"maps"`
+	APP          App                `yaml:"app"`
+	MATCH        Match              `yaml:"match"`
+	CACHE        Cache              `yaml:"cache"`
+	OVERRIDES    Overrides          `yaml:"overrides"`
+	ADMIN        Admin              `yaml:"admin"`
+	SAVED_PLACES SavedPlaces        `yaml:"saved_places"`
+	VEHICLES     map[string]Vehicle `yaml:"vehicles"`
+	ELEVATION    Elevation          `yaml:"elevation"`
+	TIMEZONE     TimeZone           `yaml:"timezone"`
+	CONSENSUS    Consensus          `yaml:"consensus"`
+	COMPARE      Compare            `yaml:"compare"`
 }
 
 const defaultPath string = "config.yaml"
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+// autocompleteMinLength avoids paying for suggestions on the first keystrokes
+const autocompleteMinLength = 3
+
+// newSessionToken returns a random UUID v4 for the autocomplete session
+func newSessionToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Autocomplete returns type-ahead suggestions, the response echoes the input so
+// clients can drop responses that arrive after a newer keystroke, and the session
+// token that must be sent to /places/{id} when the customer picks a suggestion
+func Autocomplete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Input        string   `json:"input"`
+		Lat          *float64 `json:"lat"`
+		Lng          *float64 `json:"lng"`
+		SessionToken string   `json:"session_token"`
+		Language     string   `json:"language"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.SessionToken == "" {
+		body.SessionToken = newSessionToken()
+	}
+
+	autocomplete := &entity.Autocomplete{
+		Input:        body.Input,
+		SessionToken: body.SessionToken,
+		Suggestions:  []*entity.Suggestion{},
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	input := strings.TrimSpace(body.Input)
+	if utf8.RuneCountInString(input) < autocompleteMinLength {
+		w.WriteHeader(http.StatusOK)
+		result.Status = status.ZERO_RESULTS
+		result.Message = status.OK_MESSAGE
+		result.Data = autocomplete
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	var location *entity.Location
+	if body.Lat != nil && body.Lng != nil {
+		location = &entity.Location{Lat: *body.Lat, Lng: *body.Lng}
+	}
+
+	statusMaps, suggestions, err := mMap.Autocomplete(input, location, body.SessionToken, requestLanguage(r, body.Language))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+	} else {
+		autocomplete.Suggestions = suggestions
+		w.WriteHeader(http.StatusOK)
+		result.Status = statusMaps
+		result.Message = status.OK_MESSAGE
+		result.Data = autocomplete
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"maps.patio.com/repository"
+	status "maps.patio.com/responses"
+)
+
+var consensus *repository.Consensus
+
+func NewConsensus(c *repository.Consensus) {
+	consensus = c
+}
+
+// ConsensusGeocoding geocodes with every consensus provider, addresses with
+// "needs_review" set should go to manual review before dispatching
+func ConsensusGeocoding(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Address  string `json:"address"`
+		Language string `json:"language"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if consensus == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		result.Status = status.FAILED
+		result.Message = "consensus geocoding is not configured"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	address := strings.TrimSpace(body.Address)
+	if address == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	statusMaps, merged, err := consensus.Geocoding(address, requestLanguage(r, body.Language))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = statusMaps
+		result.Message = status.OK_MESSAGE
+		result.Data = merged
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	"maps.patio.com/repository"
+	status "maps.patio.com/responses"
+)
+
+var elevator repository.Elevator
+
+// maxElevationSamples bounds the provider calls of a single request
+const maxElevationSamples = 1024
+
+func NewElevation(e repository.Elevator) {
+	elevator = e
+}
+
+var errTooManySamples = errors.New("too many samples")
+
+// resample returns a point every interval meters along the polyline, the last
+// point included, the count is checked before any point is built
+func resample(points []*entity.Location, interval float64) ([]*entity.Location, error) {
+	if interval <= 0 || len(points) < 2 {
+		return points, nil
+	}
+	cumulative := geometry.Cumulative(points)
+	total := cumulative[len(cumulative)-1]
+	if math.Ceil(total/interval)+1 > maxElevationSamples {
+		return nil, errTooManySamples
+	}
+	list := []*entity.Location{}
+	for along := 0.0; along < total; along += interval {
+		list = append(list, geometry.Interpolate(points, cumulative, along))
+	}
+	return append(list, points[len(points)-1]), nil
+}
+
+// elevationProfile accumulates the climbs and drops between consecutive samples
+func elevationProfile(points []*entity.Location, elevations []float64) *entity.Elevation {
+	profile := &entity.Elevation{
+		Points: []*entity.ElevationPoint{},
+		Min:    math.Inf(1),
+		Max:    math.Inf(-1),
+	}
+	distance := 0.0
+	for i, v := range points {
+		if i > 0 {
+			distance += geometry.Haversine(points[i-1], v)
+			delta := elevations[i] - elevations[i-1]
+			if delta > 0 {
+				profile.Ascent += delta
+			} else {
+				profile.Descent -= delta
+			}
+		}
+		profile.Min = math.Min(profile.Min, elevations[i])
+		profile.Max = math.Max(profile.Max, elevations[i])
+		profile.Points = append(profile.Points, &entity.ElevationPoint{
+			Location:  v,
+			Elevation: elevations[i],
+			Distance:  distance,
+		})
+	}
+	if len(points) == 0 {
+		profile.Min, profile.Max = 0, 0
+	}
+	return profile
+}
+
+// elevationPoints reads the points, an encoded polyline or the route between
+// origin and destination, in that order of preference
+func elevationPoints(r *http.Request, body *elevationRequest) ([]*entity.Location, string, error) {
+	switch {
+	case len(body.Points) > 0:
+		for _, v := range body.Points {
+			if v == nil {
+				return nil, status.INVALID_DATA, errors.New(status.INVALID_DATA_MESSAGE + " 'points'")
+			}
+		}
+		return body.Points, status.OK, nil
+	case body.Polyline != "":
+		var points []*entity.Location
+		var err error
+		if body.Encoding == ENCODING_FLEXPOLYLINE {
+			points, err = geometry.DecodeFlexPolyline(body.Polyline)
+		} else {
+			points, err = geometry.DecodePolyline(body.Polyline)
+		}
+		if err != nil || len(points) == 0 {
+			return nil, status.INVALID_DATA, errors.New(status.INVALID_DATA_MESSAGE + " 'polyline'")
+		}
+		return points, status.OK, nil
+	case body.Origin != nil && body.Destination != nil:
+		options, err := body.toEntity()
+		if err != nil {
+			return nil, status.INVALID_DATA, errors.New(status.INVALID_DATA_MESSAGE + routeOptionsFields)
+		}
+		statusMaps, route, err := mMap.Route(body.Origin, body.Destination, options, requestLanguage(r, ""))
+		if err != nil {
+			return nil, statusMaps, err
+		}
+		return route.Polyline, status.OK, nil
+	}
+	return nil, status.MISSING_PARAMS, errors.New(status.MISSING_PARAMS_MESSAGE)
+}
+
+type elevationRequest struct {
+	Points      []*entity.Location `json:"points"`
+	Polyline    string             `json:"polyline"`
+	Encoding    string             `json:"encoding"`
+	Origin      *entity.Location   `json:"origin"`
+	Destination *entity.Location   `json:"destination"`
+	Interval    float64            `json:"interval"`
+	routeOptions
+}
+
+// Elevation returns the elevation profile with the total ascent and descent
+func Elevation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body elevationRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if elevator == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		result.Status = status.FAILED
+		result.Message = "elevation provider is not configured"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Interval < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'interval'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	points, statusPoints, err := elevationPoints(r, &body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusPoints
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	points, err = resample(points, body.Interval)
+	if err != nil || len(points) > maxElevationSamples {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'interval', too many samples"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	statusMaps, elevations, err := elevator.Elevation(points)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = statusMaps
+		result.Message = status.OK_MESSAGE
+		result.Data = elevationProfile(points, elevations)
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
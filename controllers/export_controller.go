@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/export"
+	status "maps.patio.com/responses"
+)
+
+// parseLatLng parses a "lat,lng" query value
+func parseLatLng(value string) (*entity.Location, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return nil, strconv.ErrSyntax
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return nil, err
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Location{Lat: lat, Lng: lng}, nil
+}
+
+func RouteExport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	query := r.URL.Query()
+	var body RouteRequest
+
+	if r.Method == http.MethodGet {
+		if query.Get("origin") == "" || query.Get("destination") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.MISSING_PARAMS
+			result.Message = status.MISSING_PARAMS_MESSAGE
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+		origin, errOrigin := parseLatLng(query.Get("origin"))
+		destination, errDestination := parseLatLng(query.Get("destination"))
+		if errOrigin != nil || errDestination != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.INVALID_DATA
+			result.Message = status.INVALID_DATA_MESSAGE + " 'origin' or 'destination'"
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+		body.Origin = origin
+		body.Destination = destination
+		body.Language = query.Get("language")
+		body.DepartureTime = query.Get("departure_time")
+		body.ArrivalTime = query.Get("arrival_time")
+		body.TrafficModel = query.Get("traffic_model")
+		body.Vehicle = query.Get("vehicle")
+	} else if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Origin == nil || body.Destination == nil || query.Get("format") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	format := strings.ToLower(query.Get("format"))
+	if format != export.FORMAT_GPX && format != export.FORMAT_KML {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'format'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	routeOpts, err := body.toEntity()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + routeOptionsFields
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	statusMaps, route, err := mMap.Route(body.Origin, body.Destination, routeOpts, requestLanguage(r, body.Language))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	options := &export.Options{
+		Name:     query.Get("name"),
+		Segments: query.Get("segments") == "true",
+	}
+	if options.Name == "" {
+		options.Name = "Route"
+	}
+
+	data, contentType, err := export.Export(format, route, body.Origin, body.Destination, options)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		result.Status = status.FAILED
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=route."+format)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"maps.patio.com/geojson"
+)
+
+// wantsGeoJSON reports whether the client asked for GeoJSON with ?format=geojson or the Accept header
+func wantsGeoJSON(r *http.Request) bool {
+	if strings.EqualFold(r.URL.Query().Get("format"), "geojson") {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), geojson.ContentType)
+}
+
+func writeGeoJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", geojson.ContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(data)
+}
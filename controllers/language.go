@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var defaultLanguage string
+
+// NewLanguage sets the language used when the request does not ask for one
+func NewLanguage(language string) {
+	defaultLanguage = language
+}
+
+// validLanguage accepts BCP 47 like tags such as "es", "es-BO" or "en-US"
+func validLanguage(tag string) bool {
+	if tag == "" || len(tag) > 35 {
+		return false
+	}
+	for _, part := range strings.Split(tag, "-") {
+		if part == "" || len(part) > 8 {
+			return false
+		}
+		for _, c := range part {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// acceptLanguage returns the tag with the highest weight in the Accept-Language header
+func acceptLanguage(header string) string {
+	best, bestWeight := "", 0.0
+	for _, item := range strings.Split(header, ",") {
+		parts := strings.Split(item, ";")
+		tag := strings.TrimSpace(parts[0])
+		weight := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					q = 0
+				}
+				weight = q
+			}
+		}
+		if tag != "*" && validLanguage(tag) && weight > bestWeight {
+			best, bestWeight = tag, weight
+		}
+	}
+	return best
+}
+
+// invalidLanguage reports a language field that is set but is not a valid tag,
+// it is refused rather than answered in another language
+func invalidLanguage(language string) bool {
+	language = strings.TrimSpace(language)
+	return language != "" && !validLanguage(language)
+}
+
+// requestLanguage picks the language field of the request, then the
+// Accept-Language header and finally the configured default
+func requestLanguage(r *http.Request, language string) string {
+	language = strings.TrimSpace(language)
+	if validLanguage(language) {
+		return language
+	}
+	if header := acceptLanguage(r.Header.Get("Accept-Language")); header != "" {
+		return header
+	}
+	return defaultLanguage
+}
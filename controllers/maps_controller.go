@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"maps.patio.com/entity"
+	"maps.patio.com/geojson"
 	"maps.patio.com/repository"
 	status "maps.patio.com/responses"
 )
@@ -16,6 +17,25 @@ type Response struct {
 	Status  string      `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	Next    string      `json:"next,omitempty"`
+}
+
+// MAX_SEARCH_LIMIT caps the page size a client can ask for
+const MAX_SEARCH_LIMIT = 100
+
+// searchLimit reads the optional page size, zero leaves it to the provider
+func searchLimit(value interface{}) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid limit %v", value)
+	}
+	if limit > MAX_SEARCH_LIMIT {
+		limit = MAX_SEARCH_LIMIT
+	}
+	return limit, nil
 }
 
 var mMap repository.Repository
@@ -55,12 +75,23 @@ func Geocoding(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
 			result.Status = status.MISSING_PARAMS
 			result.Message = status.EMPTY_FIELD_MESSAGE
+		} else if body["language"] != nil && invalidLanguage(fmt.Sprint(body["language"])) {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.INVALID_DATA
+			result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
 		} else {
-			statusMaps, location, err := mMap.Geocoding(addr)
+			language := ""
+			if body["language"] != nil {
+				language = fmt.Sprint(body["language"])
+			}
+			statusMaps, location, err := mMap.Geocoding(addr, requestLanguage(r, language))
 			if err != nil {
 				w.WriteHeader(http.StatusBadRequest)
 				result.Status = statusMaps
 				result.Message = err.Error()
+			} else if wantsGeoJSON(r) {
+				writeGeoJSON(w, geojson.FromAddresses([]*entity.Address{location}))
+				return
 			} else {
 				w.WriteHeader(http.StatusOK)
 				result.Status = statusMaps
@@ -77,7 +108,10 @@ func ReverseGeocoding(w http.ResponseWriter, r *http.Request) {
 
 	result := Response{}
 
-	var body entity.Location
+	var body struct {
+		entity.Location
+		Language string `json:"language"`
+	}
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
@@ -88,11 +122,22 @@ func ReverseGeocoding(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statusMaps, address, err := mMap.ReverseGeocoding(&body)
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	statusMaps, address, err := mMap.ReverseGeocoding(&body.Location, requestLanguage(r, body.Language))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		result.Status = statusMaps
 		result.Message = err.Error()
+	} else if wantsGeoJSON(r) {
+		writeGeoJSON(w, geojson.FromAddresses([]*entity.Address{address}))
+		return
 	} else {
 		w.WriteHeader(http.StatusOK)
 		result.Status = statusMaps
@@ -131,25 +176,61 @@ func Search(w http.ResponseWriter, r *http.Request) {
 				result.Message = status.INVALID_DATA_MESSAGE + " 'lng'"
 			} else {
 				addr := fmt.Sprint(body["address"])
+				limit, errLimit := searchLimit(body["limit"])
+				cursor := ""
+				if body["cursor"] != nil {
+					cursor = fmt.Sprint(body["cursor"])
+				}
+				language := ""
+				if body["language"] != nil {
+					language = fmt.Sprint(body["language"])
+				}
 				if len(strings.TrimSpace(addr)) == 0 {
 					w.WriteHeader(http.StatusBadRequest)
 					result.Status = status.MISSING_PARAMS
 					result.Message = status.EMPTY_FIELD_MESSAGE
+				} else if errLimit != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					result.Status = status.INVALID_DATA
+					result.Message = status.INVALID_DATA_MESSAGE + " 'limit'"
+				} else if invalidLanguage(language) {
+					w.WriteHeader(http.StatusBadRequest)
+					result.Status = status.INVALID_DATA
+					result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
 				} else {
 					location := &entity.Location{
 						Lat: lat,
 						Lng: lng,
 					}
-					statusMaps, places, err := mMap.Search(addr, location)
+					saved := []*entity.Address{}
+					if customerID, ok := body["customer_id"]; ok && customerID != nil && cursor == "" {
+						saved = customerPlaces(r, fmt.Sprint(customerID), addr, limit)
+					}
+					providerLimit := limit
+					if limit > 0 {
+						providerLimit = limit - len(saved)
+					}
+					statusMaps, places, next, err := mMap.Search(addr, location, providerLimit, cursor, requestLanguage(r, language))
+					if len(saved) > 0 {
+						if err != nil {
+							places, statusMaps, err = saved, status.OK, nil
+						} else {
+							places = append(saved, places...)
+						}
+					}
 					if err != nil {
 						w.WriteHeader(http.StatusBadRequest)
 						result.Status = statusMaps
 						result.Message = err.Error()
+					} else if wantsGeoJSON(r) {
+						writeGeoJSON(w, geojson.FromAddresses(places))
+						return
 					} else {
 						w.WriteHeader(http.StatusOK)
 						result.Status = statusMaps
 						result.Message = status.OK_MESSAGE
 						result.Data = places
+						result.Next = next
 					}
 				}
 			}
@@ -161,7 +242,12 @@ func Search(w http.ResponseWriter, r *http.Request) {
 func Distance(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	result := Response{}
-	var body map[string]*entity.Location
+	var body struct {
+		Origin      *entity.Location `json:"origin"`
+		Destination *entity.Location `json:"destination"`
+		Language    string           `json:"language"`
+		routeOptions
+	}
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
@@ -172,7 +258,7 @@ func Distance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body["origin"] == nil || body["destination"] == nil {
+	if body.Origin == nil || body.Destination == nil {
 		w.WriteHeader(http.StatusBadRequest)
 		result.Status = status.MISSING_PARAMS
 		result.Message = status.MISSING_PARAMS_MESSAGE
@@ -180,11 +266,31 @@ func Distance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statusMaps, route, err := mMap.Distance(body["origin"], body["destination"])
+	options, err := body.toEntity()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + routeOptionsFields
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	statusMaps, route, err := mMap.Distance(body.Origin, body.Destination, options, requestLanguage(r, body.Language))
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		result.Status = statusMaps
 		result.Message = err.Error()
+	} else if wantsGeoJSON(r) {
+		writeGeoJSON(w, geojson.FromSummary(body.Origin, body.Destination, route))
+		return
 	} else {
 		w.WriteHeader(http.StatusOK)
 		result.Status = statusMaps
@@ -199,7 +305,7 @@ func Route(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	result := Response{}
-	var body map[string]*entity.Location
+	var body RouteRequest
 	err := json.NewDecoder(r.Body).Decode(&body)
 
 	if err != nil {
@@ -210,7 +316,7 @@ func Route(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if body["origin"] == nil || body["destination"] == nil {
+	if body.Origin == nil || body.Destination == nil {
 		w.WriteHeader(http.StatusBadRequest)
 		result.Status = status.MISSING_PARAMS
 		result.Message = status.MISSING_PARAMS_MESSAGE
@@ -218,11 +324,52 @@ func Route(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statusMaps, route, err := mMap.Route(body["origin"], body["destination"])
+	if body.Tolerance < 0 || body.MaxPoints < 0 || body.MaxPoints == 1 || !validEncoding(body.Encoding) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'tolerance', 'max_points' or 'encoding'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	options, err := body.toEntity()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + routeOptionsFields
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if wantsGeoJSON(r) {
+		body.Encoding = ""
+	}
+
+	statusMaps, route, err := mMap.Route(body.Origin, body.Destination, options, requestLanguage(r, body.Language))
+	if err == nil {
+		if err = formatRoute(route, &body); err != nil {
+			statusMaps = status.FAILED
+		}
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		result.Status = statusMaps
 		result.Message = err.Error()
+	} else if wantsGeoJSON(r) {
+		if len(route.Alternatives) > 0 {
+			writeGeoJSON(w, geojson.FromRoutes(route))
+		} else {
+			writeGeoJSON(w, geojson.FromRoute(route))
+		}
+		return
 	} else {
 		w.WriteHeader(http.StatusOK)
 		result.Status = statusMaps
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/repository"
+	status "maps.patio.com/responses"
+)
+
+var matcher repository.Matcher
+var maxTracePoints int
+
+// defaultMaxTracePoints bounds the local matching cost and fits public OSRM servers
+const defaultMaxTracePoints = 100
+
+func NewMatcher(m repository.Matcher, maxPoints int) {
+	matcher = m
+	maxTracePoints = maxPoints
+	if maxTracePoints <= 0 {
+		maxTracePoints = defaultMaxTracePoints
+	}
+}
+
+func Match(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Points []*entity.TracePoint `json:"points"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if len(body.Points) < 2 {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if len(body.Points) > maxTracePoints {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = fmt.Sprintf("%s 'points', at most %d", status.INVALID_DATA_MESSAGE, maxTracePoints)
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	// timestamps must be strictly increasing, the duration is the time between the ends
+	for i, v := range body.Points {
+		if v == nil || (i > 0 && body.Points[i-1] != nil && v.Timestamp <= body.Points[i-1].Timestamp) {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.INVALID_DATA
+			result.Message = status.INVALID_DATA_MESSAGE + " 'points'"
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+	}
+
+	statusMaps, match, err := matcher.Match(body.Points)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = statusMaps
+		result.Message = status.OK_MESSAGE
+		result.Data = match
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
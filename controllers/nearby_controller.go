@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	status "maps.patio.com/responses"
+)
+
+const (
+	nearbyDefaultRadius = 1000
+	nearbyMaxRadius     = 50000
+)
+
+func validCategories(categories []string) bool {
+	for _, v := range categories {
+		found := false
+		for _, c := range entity.Categories {
+			if v == c {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func Nearby(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Lat        *float64 `json:"lat"`
+		Lng        *float64 `json:"lng"`
+		Radius     float64  `json:"radius"`
+		Categories []string `json:"categories"`
+		Language   string   `json:"language"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Lat == nil || body.Lng == nil || len(body.Categories) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Radius == 0 {
+		body.Radius = nearbyDefaultRadius
+	}
+	if body.Radius < 0 || body.Radius > nearbyMaxRadius || !validCategories(body.Categories) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'radius' or 'categories'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	location := &entity.Location{Lat: *body.Lat, Lng: *body.Lng}
+	statusMaps, places, err := mMap.Nearby(location, body.Radius, body.Categories, requestLanguage(r, body.Language))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	// Providers rank by relevance and may return places just outside the radius
+	list := []*entity.POI{}
+	for _, v := range places {
+		v.Distance = geometry.Haversine(location, v.Location)
+		if v.Distance <= body.Radius {
+			list = append(list, v)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Distance < list[j].Distance
+	})
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = statusMaps
+	result.Message = status.OK_MESSAGE
+	result.Data = list
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"maps.patio.com/entity"
+	"maps.patio.com/overrides"
+	status "maps.patio.com/responses"
+)
+
+var overridesStore *overrides.Store
+var adminToken string
+var feedbackToken string
+
+func NewOverrides(store *overrides.Store, admin string, feedback string) {
+	overridesStore = store
+	adminToken = admin
+	feedbackToken = feedback
+}
+
+// hasToken reports whether the request carries the bearer token, always false
+// when no token is configured
+func hasToken(r *http.Request, expected string) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// tokenOnly rejects requests without the bearer token
+func tokenOnly(expected func() string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !hasToken(r, expected()) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(Response{
+				Status:  status.DENIED,
+				Message: status.DENIED_MESSAGE,
+			})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// AdminOnly rejects requests without the configured admin token, admin
+// endpoints stay closed when no token is configured
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return tokenOnly(func() string { return adminToken }, next)
+}
+
+// FeedbackOnly rejects reports without the feedback token, anyone could
+// otherwise fill the review queue
+func FeedbackOnly(next http.HandlerFunc) http.HandlerFunc {
+	return tokenOnly(func() string { return feedbackToken }, next)
+}
+
+func validLocation(location *entity.Location) bool {
+	return location.Lat >= -90 && location.Lat <= 90 && location.Lng >= -180 && location.Lng <= 180
+}
+
+func writeStoreError(w http.ResponseWriter, err error) {
+	result := Response{}
+	if err == overrides.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		result.Status = status.ZERO_RESULTS
+	} else if err == overrides.ErrConflict {
+		w.WriteHeader(http.StatusConflict)
+		result.Status = status.INVALID_DATA
+	} else if err == overrides.ErrFull {
+		w.WriteHeader(http.StatusTooManyRequests)
+		result.Status = status.DENIED
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		result.Status = status.UNKNOWN
+	}
+	result.Message = err.Error()
+	json.NewEncoder(w).Encode(result)
+}
+
+func ListOverrides(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+		Data:    overridesStore.List(),
+	})
+}
+
+func SaveOverride(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body entity.Override
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if strings.TrimSpace(body.Query) == "" || body.Location == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	body.ID = mux.Vars(r)["id"]
+	if body.Name == "" {
+		body.Name = body.Query
+	}
+	if body.Address == "" {
+		body.Address = body.Query
+	}
+
+	override, err := overridesStore.Save(&body)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = override
+	json.NewEncoder(w).Encode(result)
+}
+
+func DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := overridesStore.Delete(mux.Vars(r)["id"]); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+	})
+}
+
+func Feedback(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body entity.Feedback
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if strings.TrimSpace(body.Query) == "" || body.Delivered == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if !validLocation(body.Delivered) || (body.Returned != nil && !validLocation(body.Returned)) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'delivered' or 'returned'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	feedback, err := overridesStore.AddFeedback(&body)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = feedback
+	json.NewEncoder(w).Encode(result)
+}
+
+func ListFeedback(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+		Data:    overridesStore.Feedback(strings.ToUpper(r.URL.Query().Get("status"))),
+	})
+}
+
+func ReviewFeedback(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	vars := mux.Vars(r)
+
+	// The body is optional, it lets the reviewer adjust the pinned location
+	var body *entity.Override
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.FAILED
+			result.Message = status.FAILED_MESSAGE
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+	}
+
+	feedback, err := overridesStore.Review(vars["id"], vars["action"] == "approve", body)
+	if err == overrides.ErrNotFound {
+		writeStoreError(w, err)
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = feedback
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"maps.patio.com/pluscode"
+	status "maps.patio.com/responses"
+)
+
+func PlusCodeEncode(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Lat    *float64 `json:"lat"`
+		Lng    *float64 `json:"lng"`
+		Length int      `json:"length"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Lat == nil || body.Lng == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Length == 0 {
+		body.Length = pluscode.DefaultLength
+	}
+
+	code, err := pluscode.Encode(*body.Lat, *body.Lng, body.Length)
+	if err == nil {
+		result.Data, err = pluscode.ToEntity(code)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = status.OK
+		result.Message = status.OK_MESSAGE
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+func PlusCodeDecode(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Code     string   `json:"code"`
+		Locality string   `json:"locality"`
+		Lat      *float64 `json:"lat"`
+		Lng      *float64 `json:"lng"`
+		Language string   `json:"language"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	code := strings.TrimSpace(body.Code)
+	if code == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if invalidLanguage(body.Language) {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'language'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if pluscode.IsShort(code) {
+		if body.Lat != nil && body.Lng != nil {
+			code, err = pluscode.RecoverNearest(code, *body.Lat, *body.Lng)
+		} else if strings.TrimSpace(body.Locality) != "" {
+			statusMaps, reference, errMaps := mMap.Geocoding(body.Locality, requestLanguage(r, body.Language))
+			if errMaps != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				result.Status = statusMaps
+				result.Message = errMaps.Error()
+				json.NewEncoder(w).Encode(result)
+				return
+			}
+			code, err = pluscode.RecoverNearest(code, reference.Location.Lat, reference.Location.Lng)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			result.Status = status.MISSING_PARAMS
+			result.Message = "short plus codes need a 'locality' or 'lat' and 'lng' reference"
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+	}
+
+	if err == nil {
+		result.Data, err = pluscode.ToEntity(code)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = status.OK
+		result.Message = status.OK_MESSAGE
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"maps.patio.com/entity"
+	"maps.patio.com/geojson"
+	"maps.patio.com/geometry"
+)
+
+const (
+	ENCODING_POLYLINE     = "polyline"
+	ENCODING_FLEXPOLYLINE = "flexpolyline"
+	ENCODING_GEOJSON      = "geojson"
+)
+
+type RouteRequest struct {
+	Origin      *entity.Location `json:"origin"`
+	Destination *entity.Location `json:"destination"`
+	Tolerance   float64          `json:"tolerance"`
+	MaxPoints   int              `json:"max_points"`
+	Encoding    string           `json:"encoding"`
+	Language    string           `json:"language"`
+	routeOptions
+}
+
+func validEncoding(encoding string) bool {
+	switch encoding {
+	case "", ENCODING_POLYLINE, ENCODING_FLEXPOLYLINE, ENCODING_GEOJSON:
+		return true
+	}
+	return false
+}
+
+// formatRoute applies the simplification and encoding options to the route polyline
+func formatRoute(route *entity.Route, options *RouteRequest) error {
+	points := simplifyLegs(route, options.Tolerance)
+	if limited := geometry.Limit(points, options.MaxPoints); len(limited) != len(points) {
+		points = limited
+		route.Legs = nil
+	}
+
+	switch options.Encoding {
+	case ENCODING_POLYLINE:
+		route.Geometry = geometry.EncodePolyline(points)
+	case ENCODING_FLEXPOLYLINE:
+		encoded, err := geometry.EncodeFlexPolyline(points)
+		if err != nil {
+			return err
+		}
+		route.Geometry = encoded
+	case ENCODING_GEOJSON:
+		route.Geometry = geojson.NewLineString(points)
+	default:
+		route.Polyline = points
+		return formatAlternatives(route, options)
+	}
+
+	route.Encoding = options.Encoding
+	route.Polyline = nil
+	return formatAlternatives(route, options)
+}
+
+func formatAlternatives(route *entity.Route, options *RouteRequest) error {
+	for _, v := range route.Alternatives {
+		if err := formatRoute(v, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simplifyLegs simplifies every leg on its own so the leg indexes stay valid
+func simplifyLegs(route *entity.Route, tolerance float64) []*entity.Location {
+	if tolerance <= 0 {
+		return route.Polyline
+	}
+	if len(route.Legs) == 0 {
+		return geometry.Simplify(route.Polyline, tolerance)
+	}
+
+	list := []*entity.Location{}
+	for _, leg := range route.Legs {
+		simplified := geometry.Simplify(route.Polyline[leg.Start:leg.End+1], tolerance)
+		if len(list) > 0 && len(simplified) > 0 {
+			simplified = simplified[1:]
+		}
+		leg.Start = len(list) - 1
+		if leg.Start < 0 {
+			leg.Start = 0
+		}
+		list = append(list, simplified...)
+		leg.End = len(list) - 1
+	}
+	return list
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+// routeOptions are the optional fields shared by /distance and /route
+type routeOptions struct {
+	DepartureTime string              `json:"departure_time"`
+	ArrivalTime   string              `json:"arrival_time"`
+	TrafficModel  string              `json:"traffic_model"`
+	Avoid         []string            `json:"avoid"`
+	AvoidAreas    []*entity.AvoidArea `json:"avoid_areas"`
+	Alternatives  int                 `json:"alternatives"`
+	Steps         bool                `json:"steps"`
+	Vehicle       string              `json:"vehicle"`
+}
+
+const routeOptionsFields = " 'departure_time', 'arrival_time', 'traffic_model', 'avoid', 'avoid_areas', 'alternatives' or 'vehicle'"
+
+var vehicles map[string]*entity.VehicleProfile
+
+func NewVehicles(profiles map[string]*entity.VehicleProfile) {
+	vehicles = profiles
+}
+
+// Vehicles lists the configured vehicle profiles that can be sent as "vehicle"
+func Vehicles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	list := []*entity.VehicleProfile{}
+	for _, v := range vehicles {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+		Data:    list,
+	})
+}
+
+// maxAlternatives is the most alternative routes both providers can return
+const maxAlternatives = 3
+
+func validAvoid(avoid []string) bool {
+	for _, v := range avoid {
+		found := false
+		for _, feature := range entity.AvoidFeatures {
+			if v == feature {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// validArea requires a bounding box with both corners or a polygon of at least three points
+func validArea(area *entity.AvoidArea) bool {
+	if area == nil {
+		return false
+	}
+	if area.BoundingBox != nil {
+		return len(area.Polygon) == 0 && area.BoundingBox.Northeast != nil && area.BoundingBox.Southwest != nil &&
+			area.BoundingBox.Northeast.Lat > area.BoundingBox.Southwest.Lat
+	}
+	if len(area.Polygon) < 3 {
+		return false
+	}
+	for _, v := range area.Polygon {
+		if v == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTime accepts "now", RFC 3339 or unix seconds
+func parseTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if value == "now" {
+		now := time.Now()
+		return &now, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		t := time.Unix(seconds, 0)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (o *routeOptions) toEntity() (*entity.RouteOptions, error) {
+	departure, err := parseTime(o.DepartureTime)
+	if err != nil {
+		return nil, err
+	}
+	arrival, err := parseTime(o.ArrivalTime)
+	if err != nil {
+		return nil, err
+	}
+	if departure != nil && arrival != nil {
+		return nil, errors.New("departure_time and arrival_time are exclusive")
+	}
+
+	switch o.TrafficModel {
+	case "", entity.TRAFFIC_BEST_GUESS, entity.TRAFFIC_PESSIMISTIC, entity.TRAFFIC_OPTIMISTIC:
+	default:
+		return nil, errors.New("invalid traffic_model " + o.TrafficModel)
+	}
+
+	if !validAvoid(o.Avoid) {
+		return nil, errors.New("invalid avoid feature")
+	}
+	for _, area := range o.AvoidAreas {
+		if !validArea(area) {
+			return nil, errors.New("invalid avoid area")
+		}
+	}
+	if o.Alternatives < 0 || o.Alternatives > maxAlternatives {
+		return nil, errors.New("invalid number of alternatives")
+	}
+
+	var vehicle *entity.VehicleProfile
+	if o.Vehicle != "" {
+		profile, ok := vehicles[o.Vehicle]
+		if !ok {
+			return nil, errors.New("unknown vehicle " + o.Vehicle)
+		}
+		vehicle = profile
+	}
+
+	return &entity.RouteOptions{
+		DepartureTime: departure,
+		ArrivalTime:   arrival,
+		TrafficModel:  o.TrafficModel,
+		Avoid:         o.Avoid,
+		AvoidAreas:    o.AvoidAreas,
+		Alternatives:  o.Alternatives,
+		Steps:         o.Steps,
+		Vehicle:       vehicle,
+	}, nil
+}
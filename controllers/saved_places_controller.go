@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+	"maps.patio.com/savedplaces"
+)
+
+var savedPlaces *savedplaces.Store
+var savedPlacesToken string
+
+func NewSavedPlaces(store *savedplaces.Store, token string) {
+	savedPlaces = store
+	savedPlacesToken = token
+}
+
+// SavedPlacesOnly rejects requests without the saved places token, the
+// addresses are personal data so the endpoints stay closed without a token
+func SavedPlacesOnly(next http.HandlerFunc) http.HandlerFunc {
+	return tokenOnly(func() string { return savedPlacesToken }, next)
+}
+
+// customerPlaces returns the customer places matching the query, only for
+// requests carrying the saved places token, they go before the provider
+// results and leave at least one slot of the page to the provider, each one
+// carries the saved place so the app keeps its ID, label and notes
+func customerPlaces(r *http.Request, customerID string, query string, limit int) []*entity.Address {
+	list := []*entity.Address{}
+	if savedPlaces == nil || customerID == "" || !hasToken(r, savedPlacesToken) {
+		return list
+	}
+
+	for _, v := range savedPlaces.Match(customerID, query) {
+		if limit > 0 && len(list) >= limit-1 {
+			break
+		}
+		list = append(list, &entity.Address{
+			Name:       v.Name,
+			Address:    v.Address,
+			Location:   v.Location,
+			SavedPlace: v,
+		})
+	}
+	return list
+}
+
+func writeSavedPlaceError(w http.ResponseWriter, err error) {
+	result := Response{}
+	if err == savedplaces.ErrNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		result.Status = status.ZERO_RESULTS
+	} else if err == savedplaces.ErrConflict {
+		w.WriteHeader(http.StatusConflict)
+		result.Status = status.INVALID_DATA
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		result.Status = status.UNKNOWN
+	}
+	result.Message = err.Error()
+	json.NewEncoder(w).Encode(result)
+}
+
+func ListSavedPlaces(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+		Data:    savedPlaces.List(mux.Vars(r)["customer"]),
+	})
+}
+
+func GetSavedPlace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	place, err := savedPlaces.Get(vars["customer"], vars["id"])
+	if err != nil {
+		writeSavedPlaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+		Data:    place,
+	})
+}
+
+func SaveSavedPlace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body entity.SavedPlace
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if strings.TrimSpace(body.Label) == "" || body.Location == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	vars := mux.Vars(r)
+	body.CustomerID = vars["customer"]
+	body.ID = vars["id"]
+	if body.Name == "" {
+		body.Name = body.Label
+	}
+
+	place, err := savedPlaces.Save(&body)
+	if err != nil {
+		writeSavedPlaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = place
+	json.NewEncoder(w).Encode(result)
+}
+
+func DeleteSavedPlace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	if err := savedPlaces.Delete(vars["customer"], vars["id"]); err != nil {
+		writeSavedPlaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(Response{
+		Status:  status.OK,
+		Message: status.OK_MESSAGE,
+	})
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/repository"
+	status "maps.patio.com/responses"
+)
+
+var timeZoner repository.TimeZoner
+
+func NewTimeZone(t repository.TimeZoner) {
+	timeZoner = t
+}
+
+// TimeZone returns the IANA zone of the location and its offset now or at "timestamp"
+func TimeZone(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body struct {
+		Lat       *float64 `json:"lat"`
+		Lng       *float64 `json:"lng"`
+		Timestamp string   `json:"timestamp"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if timeZoner == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		result.Status = status.FAILED
+		result.Message = "timezone lookup is not configured"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Lat == nil || body.Lng == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	at, err := parseTime(body.Timestamp)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = status.INVALID_DATA_MESSAGE + " 'timestamp'"
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+	if at == nil {
+		now := time.Now()
+		at = &now
+	}
+
+	location := &entity.Location{Lat: *body.Lat, Lng: *body.Lng}
+	statusMaps, zone, err := timeZoner.TimeZone(location, *at)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = statusMaps
+		result.Message = err.Error()
+	} else {
+		w.WriteHeader(http.StatusOK)
+		result.Status = statusMaps
+		result.Message = status.OK_MESSAGE
+		result.Data = zone
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	status "maps.patio.com/responses"
+	"maps.patio.com/tracking"
+)
+
+// TrackingRequest takes the route in the same shape /route returns it
+type TrackingRequest struct {
+	Summary   entity.Summary     `json:"summary"`
+	Polyline  []*entity.Location `json:"polyline"`
+	Encoding  string             `json:"encoding"`
+	Geometry  json.RawMessage    `json:"geometry"`
+	Position  *entity.Location   `json:"position"`
+	Travelled float64            `json:"travelled"`
+	Threshold float64            `json:"threshold"`
+	Distance  *float64           `json:"distance"`
+}
+
+func (t *TrackingRequest) route() (*entity.Route, error) {
+	route := &entity.Route{
+		Summary:  t.Summary,
+		Polyline: t.Polyline,
+	}
+	if t.Encoding == "" {
+		if len(route.Polyline) == 0 {
+			return nil, errors.New("route polyline is empty")
+		}
+		for _, v := range route.Polyline {
+			if v == nil {
+				return nil, errors.New("route polyline has an empty point")
+			}
+		}
+		return route, nil
+	}
+
+	var err error
+	if t.Encoding == ENCODING_GEOJSON {
+		var line struct {
+			Coordinates [][]float64 `json:"coordinates"`
+		}
+		err = json.Unmarshal(t.Geometry, &line)
+		route.Polyline = []*entity.Location{}
+		for _, v := range line.Coordinates {
+			if len(v) >= 2 {
+				route.Polyline = append(route.Polyline, &entity.Location{Lat: v[1], Lng: v[0]})
+			}
+		}
+	} else {
+		var encoded string
+		if err = json.Unmarshal(t.Geometry, &encoded); err != nil {
+			return nil, err
+		}
+		switch t.Encoding {
+		case ENCODING_POLYLINE:
+			route.Polyline, err = geometry.DecodePolyline(encoded)
+		case ENCODING_FLEXPOLYLINE:
+			route.Polyline, err = geometry.DecodeFlexPolyline(encoded)
+		default:
+			err = errors.New("invalid encoding " + t.Encoding)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(route.Polyline) == 0 {
+		return nil, errors.New("route polyline is empty")
+	}
+	return route, nil
+}
+
+func RouteProgress(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body TrackingRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Position == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	route, err := body.route()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = tracking.Progress(route, body.Position, body.Travelled, body.Threshold)
+	json.NewEncoder(w).Encode(result)
+}
+
+func RouteAlong(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	result := Response{}
+	var body TrackingRequest
+	err := json.NewDecoder(r.Body).Decode(&body)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.FAILED
+		result.Message = status.FAILED_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if body.Distance == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.MISSING_PARAMS
+		result.Message = status.MISSING_PARAMS_MESSAGE
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	route, err := body.route()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		result.Status = status.INVALID_DATA
+		result.Message = err.Error()
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	result.Status = status.OK
+	result.Message = status.OK_MESSAGE
+	result.Data = tracking.PointAlong(route, *body.Distance)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,224 @@
+// Package coordinates extracts a location from the free text customers paste
+// in the address box: decimal pairs, degrees/minutes/seconds, geo: URIs and map links
+package coordinates
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"maps.patio.com/entity"
+)
+
+var (
+	decimalPattern = regexp.MustCompile(`^\(?\s*(-?\d{1,2}\.\d+)\s*[,;\s]\s*(-?\d{1,3}\.\d+)\s*\)?$`)
+	// commaPattern is a pair written with decimal commas, "-17,7992, -63,1971"
+	commaPattern = regexp.MustCompile(`^\(?\s*(-?\d{1,2},\d+)\s*[,;\s]\s*(-?\d{1,3},\d+)\s*\)?$`)
+	// hemispherePattern is a decimal with its hemisphere letter before or after, "S17.7992"
+	hemispherePattern = regexp.MustCompile(`(?i)(?:^|\s|,)([NSEWO])\s*(\d{1,3}\.\d+)|(\d{1,3}\.\d+)\s*([NSEWO])(?:$|\s|,)`)
+	pairPattern       = regexp.MustCompile(`(-?\d{1,2}\.\d+)\s*,\s*\+?(-?\d{1,3}\.\d+)`)
+	pinPattern        = regexp.MustCompile(`!3d(-?\d+\.\d+)!4d(-?\d+\.\d+)`)
+	atPattern         = regexp.MustCompile(`@(-?\d+\.\d+),(-?\d+\.\d+)`)
+	dmsPattern        = regexp.MustCompile(`(?i)(\d{1,3}(?:[.,]\d+)?)\s*[°º]\s*(?:(\d{1,2}(?:[.,]\d+)?)\s*['′’]\s*)?(?:(\d{1,2}(?:[.,]\d+)?)\s*(?:"|″|”|'')\s*)?([NSEWO])`)
+)
+
+// shortLinkHosts are link shorteners that redirect to a full maps URL
+var shortLinkHosts = []string{"maps.app.goo.gl", "goo.gl"}
+
+var client = &http.Client{
+	Timeout: 5 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Parse returns the location written in the text, ok is false when the text
+// does not look like coordinates
+func Parse(text string) (*entity.Location, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, false
+	}
+
+	if strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") {
+		return parseURL(text, 3)
+	}
+	if strings.HasPrefix(strings.ToLower(text), "geo:") {
+		return parseGeo(text)
+	}
+	if location, ok := parseDecimal(text); ok {
+		return location, true
+	}
+	if location, ok := parseHemisphere(text); ok {
+		return location, true
+	}
+	return parseDMS(text)
+}
+
+func parseDecimal(text string) (*entity.Location, bool) {
+	if match := decimalPattern.FindStringSubmatch(text); match != nil {
+		return build(match[1], match[2])
+	}
+	if match := commaPattern.FindStringSubmatch(text); match != nil {
+		return build(strings.Replace(match[1], ",", ".", 1), strings.Replace(match[2], ",", ".", 1))
+	}
+	return nil, false
+}
+
+// parseGeo reads the geo: URIs Android shares, "geo:lat,lng;u=10" or
+// "geo:0,0?q=lat,lng(label)" when the point is in the query
+func parseGeo(text string) (*entity.Location, bool) {
+	body := text[len("geo:"):]
+	query := ""
+	if i := strings.Index(body, "?"); i >= 0 {
+		body, query = body[:i], body[i+1:]
+	}
+	if i := strings.Index(body, ";"); i >= 0 {
+		body = body[:i]
+	}
+
+	if values, err := url.ParseQuery(query); err == nil {
+		if match := pairPattern.FindStringSubmatch(values.Get("q")); match != nil {
+			return build(match[1], match[2])
+		}
+	}
+	parts := strings.Split(body, ",")
+	if len(parts) < 2 {
+		return nil, false
+	}
+	location, ok := build(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	if !ok || (location.Lat == 0 && location.Lng == 0) {
+		return nil, false
+	}
+	return location, true
+}
+
+// parseHemisphere reads decimals with hemisphere letters, "S17.7992 W63.1971"
+func parseHemisphere(text string) (*entity.Location, bool) {
+	matches := hemispherePattern.FindAllStringSubmatch(text, -1)
+	if len(matches) != 2 {
+		return nil, false
+	}
+
+	var lat, lng *float64
+	for _, m := range matches {
+		letter, number := m[1], m[2]
+		if letter == "" {
+			letter, number = m[4], m[3]
+		}
+		value := toFloat(number)
+		switch strings.ToUpper(letter) {
+		case "S":
+			value = -value
+			lat = &value
+		case "N":
+			lat = &value
+		case "W", "O":
+			value = -value
+			lng = &value
+		case "E":
+			lng = &value
+		}
+	}
+	if lat == nil || lng == nil || !valid(*lat, *lng) {
+		return nil, false
+	}
+	return &entity.Location{Lat: *lat, Lng: *lng}, true
+}
+
+func parseDMS(text string) (*entity.Location, bool) {
+	matches := dmsPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) != 2 {
+		return nil, false
+	}
+
+	var lat, lng *float64
+	for _, m := range matches {
+		value := toFloat(m[1]) + toFloat(m[2])/60 + toFloat(m[3])/3600
+		switch strings.ToUpper(m[4]) {
+		case "S":
+			value = -value
+			lat = &value
+		case "N":
+			lat = &value
+		case "W", "O":
+			value = -value
+			lng = &value
+		case "E":
+			lng = &value
+		}
+	}
+	if lat == nil || lng == nil || !valid(*lat, *lng) {
+		return nil, false
+	}
+	return &entity.Location{Lat: *lat, Lng: *lng}, true
+}
+
+// parseURL understands Google Maps links, including the ones WhatsApp shares
+// for a location, short links are expanded following at most depth redirects
+func parseURL(text string, depth int) (*entity.Location, bool) {
+	uri, err := url.Parse(text)
+	if err != nil {
+		return nil, false
+	}
+
+	for _, host := range shortLinkHosts {
+		if strings.EqualFold(uri.Host, host) && depth > 0 {
+			resp, err := client.Head(text)
+			if err != nil {
+				return nil, false
+			}
+			resp.Body.Close()
+			location := resp.Header.Get("Location")
+			if location == "" {
+				return nil, false
+			}
+			return parseURL(location, depth-1)
+		}
+	}
+
+	if !strings.Contains(uri.Host, "google.") {
+		return nil, false
+	}
+
+	path, _ := url.PathUnescape(uri.Path)
+	if match := pinPattern.FindStringSubmatch(path); match != nil {
+		return build(match[1], match[2])
+	}
+
+	query := uri.Query()
+	for _, key := range []string{"q", "query", "ll", "destination", "daddr", "center"} {
+		if match := pairPattern.FindStringSubmatch(query.Get(key)); match != nil {
+			return build(match[1], match[2])
+		}
+	}
+
+	if match := pairPattern.FindStringSubmatch(path); match != nil {
+		return build(match[1], match[2])
+	}
+	if match := atPattern.FindStringSubmatch(path); match != nil {
+		return build(match[1], match[2])
+	}
+	return nil, false
+}
+
+func build(latText string, lngText string) (*entity.Location, bool) {
+	lat, errLat := strconv.ParseFloat(latText, 64)
+	lng, errLng := strconv.ParseFloat(lngText, 64)
+	if errLat != nil || errLng != nil || !valid(lat, lng) {
+		return nil, false
+	}
+	return &entity.Location{Lat: lat, Lng: lng}, true
+}
+
+func toFloat(text string) float64 {
+	value, _ := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+	return value
+}
+
+func valid(lat float64, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
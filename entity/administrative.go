@@ -0,0 +1,14 @@
+package entity
+
+// Administrative is the administrative hierarchy of an address, from the
+// country down to the neighbourhood, empty levels are omitted
+type Administrative struct {
+	Country       string `json:"country,omitempty"`
+	CountryCode   string `json:"country_code,omitempty"`
+	Department    string `json:"department,omitempty"`
+	Province      string `json:"province,omitempty"`
+	Municipality  string `json:"municipality,omitempty"`
+	District      string `json:"district,omitempty"`
+	Neighbourhood string `json:"neighbourhood,omitempty"`
+	PostalCode    string `json:"postal_code,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+// ProviderResult is the answer of one provider, Distance is meters to the consensus location
+type ProviderResult struct {
+	Provider string   `json:"provider"`
+	Status   string   `json:"status"`
+	Address  *Address `json:"address,omitempty"`
+	Distance float64  `json:"distance"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Consensus merges the providers answers, Confidence is the share of providers
+// agreeing with the merged location and Disagreement is set when any answer is
+// farther than the threshold from it, NeedsReview is also set when fewer than
+// two providers answered
+type Consensus struct {
+	Address      *Address          `json:"address"`
+	Confidence   float64           `json:"confidence"`
+	Disagreement bool              `json:"disagreement"`
+	NeedsReview  bool              `json:"needs_review"`
+	MaxDistance  float64           `json:"max_distance"`
+	Results      []*ProviderResult `json:"results"`
+}
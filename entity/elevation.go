@@ -0,0 +1,18 @@
+package entity
+
+// ElevationPoint is a sampled point, Distance is meters from the first point
+type ElevationPoint struct {
+	Location  *Location `json:"location"`
+	Elevation float64   `json:"elevation"`
+	Distance  float64   `json:"distance"`
+}
+
+// Elevation is the profile of the points in meters, Ascent and Descent are
+// the accumulated climbs and drops along the points
+type Elevation struct {
+	Points  []*ElevationPoint `json:"points"`
+	Ascent  float64           `json:"ascent"`
+	Descent float64           `json:"descent"`
+	Min     float64           `json:"min"`
+	Max     float64           `json:"max"`
+}
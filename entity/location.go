@@ -5,18 +5,39 @@ type Location struct {
 	Lng float64 `json:"lng"`
 }
 
+// Address.Administrative is only filled by reverse geocoding and SavedPlace
+// only for the customer places blended into a search
 type Address struct {
-	Name     string    `json:"name"`
-	Address  string    `json:"address"`
-	Location *Location `json:"location"`
+	PlaceID        string          `json:"place_id,omitempty"`
+	Name           string          `json:"name"`
+	Address        string          `json:"address"`
+	Location       *Location       `json:"location"`
+	Administrative *Administrative `json:"administrative,omitempty"`
+	SavedPlace     *SavedPlace     `json:"saved_place,omitempty"`
 }
 
+// Summary durations are in seconds and distances in meters, Duration is the
+// free-flow time and TrafficDuration is only set when a departure or arrival
+// time was requested
 type Summary struct {
-	Duration float64 `json:"duration"`
-	Distance float64 `json:"distance"`
+	Duration        float64 `json:"duration"`
+	TrafficDuration float64 `json:"traffic_duration,omitempty"`
+	Distance        float64 `json:"distance"`
+}
+
+// Leg is a section of the route, Start and End are inclusive indexes into Route.Polyline
+type Leg struct {
+	Summary Summary `json:"summary"`
+	Start   int     `json:"start"`
+	End     int     `json:"end"`
 }
 
 type Route struct {
-	Summary  Summary
-	Polyline []*Location `json:"polyline"`
+	Summary      Summary
+	Legs         []*Leg      `json:"legs,omitempty"`
+	Polyline     []*Location `json:"polyline,omitempty"`
+	Encoding     string      `json:"encoding,omitempty"`
+	Geometry     interface{} `json:"geometry,omitempty"`
+	Steps        []*Step     `json:"steps,omitempty"`
+	Alternatives []*Route    `json:"alternatives,omitempty"`
 }
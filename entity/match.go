@@ -0,0 +1,20 @@
+package entity
+
+type TracePoint struct {
+	Lat       float64 `json:"lat"`
+	Lng       float64 `json:"lng"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+type MatchedPoint struct {
+	Location   *Location `json:"location"`
+	Confidence float64   `json:"confidence"`
+	Matched    bool      `json:"matched"`
+}
+
+type Match struct {
+	Distance float64         `json:"distance"`
+	Duration float64         `json:"duration"`
+	Polyline []*Location     `json:"polyline"`
+	Points   []*MatchedPoint `json:"points"`
+}
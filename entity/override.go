@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	FEEDBACK_PENDING  = "PENDING"
+	FEEDBACK_APPROVED = "APPROVED"
+	FEEDBACK_REJECTED = "REJECTED"
+)
+
+// Override pins an address or place name to a location curated by ops
+type Override struct {
+	ID        string    `json:"id"`
+	Query     string    `json:"query"`
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Location  *Location `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Feedback is a report from the app that a geocoding result was wrong
+type Feedback struct {
+	ID         string    `json:"id"`
+	Query      string    `json:"query"`
+	Returned   *Location `json:"returned"`
+	Delivered  *Location `json:"delivered"`
+	Distance   float64   `json:"distance"`
+	OrderID    string    `json:"order_id,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	Status     string    `json:"status"`
+	OverrideID string    `json:"override_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+// ParsedAddress holds the components of a Bolivian street address
+type ParsedAddress struct {
+	StreetType    string `json:"street_type,omitempty"`   // calle, avenida, pasaje...
+	Street        string `json:"street,omitempty"`        // name of the street
+	Number        string `json:"number,omitempty"`        // número
+	Corner        string `json:"corner,omitempty"`        // esquina
+	Between       string `json:"between,omitempty"`       // entre
+	Ring          int    `json:"ring,omitempty"`          // anillo
+	Radial        string `json:"radial,omitempty"`        // radial
+	Neighbourhood string `json:"neighbourhood,omitempty"` // barrio
+	UV            string `json:"uv,omitempty"`            // unidad vecinal
+	Block         string `json:"block,omitempty"`         // manzano
+	Lot           string `json:"lot,omitempty"`           // lote
+	City          string `json:"city,omitempty"`          // ciudad
+}
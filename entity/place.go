@@ -0,0 +1,26 @@
+package entity
+
+type AddressComponent struct {
+	LongName  string   `json:"long_name"`
+	ShortName string   `json:"short_name"`
+	Types     []string `json:"types"`
+}
+
+type Viewport struct {
+	Northeast *Location `json:"northeast"`
+	Southwest *Location `json:"southwest"`
+}
+
+type PlaceDetails struct {
+	PlaceID      string              `json:"place_id"`
+	Name         string              `json:"name"`
+	Address      string              `json:"address"`
+	Location     *Location           `json:"location"`
+	Components   []*AddressComponent `json:"components"`
+	Phone        string              `json:"phone,omitempty"`
+	Website      string              `json:"website,omitempty"`
+	OpeningHours []string            `json:"opening_hours,omitempty"`
+	OpenNow      *bool               `json:"open_now,omitempty"`
+	Categories   []string            `json:"categories"`
+	Viewport     *Viewport           `json:"viewport,omitempty"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+type PlusCode struct {
+	Code      string    `json:"code"`
+	Location  *Location `json:"location"`
+	Southwest *Location `json:"southwest"`
+	Northeast *Location `json:"northeast"`
+	Length    int       `json:"length"`
+}
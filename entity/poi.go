@@ -0,0 +1,25 @@
+package entity
+
+const (
+	CATEGORY_PHARMACY    = "pharmacy"
+	CATEGORY_SUPERMARKET = "supermarket"
+	CATEGORY_RESTAURANT  = "restaurant"
+	CATEGORY_FUEL        = "fuel"
+)
+
+var Categories = []string{
+	CATEGORY_PHARMACY,
+	CATEGORY_SUPERMARKET,
+	CATEGORY_RESTAURANT,
+	CATEGORY_FUEL,
+}
+
+// POI is a point of interest found around a location, Distance in meters
+type POI struct {
+	PlaceID  string    `json:"place_id"`
+	Name     string    `json:"name"`
+	Address  string    `json:"address"`
+	Location *Location `json:"location"`
+	Category string    `json:"category"`
+	Distance float64   `json:"distance"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+type Progress struct {
+	Position  *Location `json:"position"`
+	Travelled float64   `json:"travelled"`
+	Remaining float64   `json:"remaining"`
+	Duration  float64   `json:"duration"`
+	Progress  float64   `json:"progress"`
+	Deviation float64   `json:"deviation"`
+	OffRoute  bool      `json:"off_route"`
+}
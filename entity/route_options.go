@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+const (
+	TRAFFIC_BEST_GUESS  = "best_guess"
+	TRAFFIC_PESSIMISTIC = "pessimistic"
+	TRAFFIC_OPTIMISTIC  = "optimistic"
+
+	AVOID_TOLLS    = "tolls"
+	AVOID_HIGHWAYS = "highways"
+	AVOID_FERRIES  = "ferries"
+	AVOID_UNPAVED  = "unpaved"
+)
+
+var AvoidFeatures = []string{AVOID_TOLLS, AVOID_HIGHWAYS, AVOID_FERRIES, AVOID_UNPAVED}
+
+// AvoidArea is either a bounding box or a closed polygon the route must not cross
+type AvoidArea struct {
+	BoundingBox *Viewport   `json:"bounding_box,omitempty"`
+	Polygon     []*Location `json:"polygon,omitempty"`
+}
+
+// RouteOptions are the optional parameters of distance and route requests,
+// DepartureTime and ArrivalTime are exclusive and enable traffic durations,
+// Alternatives and Steps only apply to routes, Steps asks for the maneuvers in
+// the request language, a nil Vehicle keeps the provider default mode
+type RouteOptions struct {
+	DepartureTime *time.Time
+	ArrivalTime   *time.Time
+	TrafficModel  string
+	Avoid         []string
+	AvoidAreas    []*AvoidArea
+	Alternatives  int
+	Steps         bool
+	Vehicle       *VehicleProfile
+}
+
+// WantsSteps reports whether the maneuvers were requested
+func (o *RouteOptions) WantsSteps() bool {
+	return o != nil && o.Steps
+}
+
+// Avoids reports whether the feature is in the avoid list
+func (o *RouteOptions) Avoids(feature string) bool {
+	if o == nil {
+		return false
+	}
+	for _, v := range o.Avoid {
+		if v == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Traffic reports whether the request asks for traffic aware durations
+func (o *RouteOptions) Traffic() bool {
+	return o != nil && (o.DepartureTime != nil || o.ArrivalTime != nil)
+}
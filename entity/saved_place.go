@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+const (
+	PLACE_HOME = "home"
+	PLACE_WORK = "work"
+)
+
+// SavedPlace is an address a customer confirmed, with notes for the courier
+type SavedPlace struct {
+	ID         string    `json:"id"`
+	CustomerID string    `json:"customer_id"`
+	Label      string    `json:"label"`
+	Name       string    `json:"name"`
+	Address    string    `json:"address"`
+	Notes      string    `json:"notes,omitempty"`
+	Location   *Location `json:"location"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
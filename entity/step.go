@@ -0,0 +1,11 @@
+package entity
+
+// Step is a maneuver of the route, Maneuver follows the Google names such as
+// "turn-left" or "roundabout-right" and is empty when the road continues straight
+type Step struct {
+	Instruction string    `json:"instruction"`
+	Maneuver    string    `json:"maneuver,omitempty"`
+	Distance    float64   `json:"distance"`
+	Duration    float64   `json:"duration"`
+	Location    *Location `json:"location"`
+}
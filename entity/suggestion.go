@@ -0,0 +1,21 @@
+package entity
+
+// Highlight is a range of the title that matches the input
+type Highlight struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+type Suggestion struct {
+	PlaceID     string       `json:"place_id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Highlights  []*Highlight `json:"highlights"`
+	Location    *Location    `json:"location,omitempty"`
+}
+
+type Autocomplete struct {
+	Input        string        `json:"input"`
+	SessionToken string        `json:"session_token"`
+	Suggestions  []*Suggestion `json:"suggestions"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+// TimeZone is the IANA zone of a location and its UTC offset in seconds at the requested time
+type TimeZone struct {
+	ID           string `json:"id"`
+	Abbreviation string `json:"abbreviation"`
+	Offset       int    `json:"offset"`
+	UTCOffset    string `json:"utc_offset"`
+	DST          bool   `json:"dst"`
+}
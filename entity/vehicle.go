@@ -0,0 +1,37 @@
+package entity
+
+const (
+	VEHICLE_CAR        = "car"
+	VEHICLE_TRUCK      = "truck"
+	VEHICLE_SCOOTER    = "scooter"
+	VEHICLE_BICYCLE    = "bicycle"
+	VEHICLE_PEDESTRIAN = "pedestrian"
+)
+
+var VehicleModes = []string{VEHICLE_CAR, VEHICLE_TRUCK, VEHICLE_SCOOTER, VEHICLE_BICYCLE, VEHICLE_PEDESTRIAN}
+
+var HazardousGoods = []string{
+	"explosive", "gas", "flammable", "combustible", "organic", "poison",
+	"radioactive", "corrosive", "poisonousInhalation", "harmfulToWater", "other",
+}
+
+// VehicleProfile describes the vehicle the route is for, dimensions are in
+// meters and weights in kilograms, zero values are not restricted
+type VehicleProfile struct {
+	Name           string   `json:"name"`
+	Mode           string   `json:"mode"`
+	Height         float64  `json:"height,omitempty"`
+	Width          float64  `json:"width,omitempty"`
+	Length         float64  `json:"length,omitempty"`
+	GrossWeight    float64  `json:"gross_weight,omitempty"`
+	WeightPerAxle  float64  `json:"weight_per_axle,omitempty"`
+	AxleCount      int      `json:"axle_count,omitempty"`
+	TrailerCount   int      `json:"trailer_count,omitempty"`
+	HazardousGoods []string `json:"hazardous_goods,omitempty"`
+}
+
+// Restricted reports whether the profile has limits beyond the transport mode
+func (v *VehicleProfile) Restricted() bool {
+	return v.Height > 0 || v.Width > 0 || v.Length > 0 || v.GrossWeight > 0 ||
+		v.WeightPerAxle > 0 || v.AxleCount > 0 || v.TrailerCount > 0 || len(v.HazardousGoods) > 0
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fmt"
+
+	"maps.patio.com/entity"
+)
+
+const (
+	FORMAT_GPX = "gpx"
+	FORMAT_KML = "kml"
+)
+
+type Options struct {
+	Name     string
+	Segments bool
+}
+
+// Export writes the route in the requested format, returns the document and its content type
+func Export(format string, route *entity.Route, origin *entity.Location, destination *entity.Location, options *Options) ([]byte, string, error) {
+	switch format {
+	case FORMAT_GPX:
+		data, err := GPX(route, origin, destination, options)
+		return data, "application/gpx+xml", err
+	case FORMAT_KML:
+		data, err := KML(route, origin, destination, options)
+		return data, "application/vnd.google-earth.kml+xml", err
+	default:
+		return nil, "", fmt.Errorf("invalid export format %v", format)
+	}
+}
+
+// segments splits the polyline by leg when requested, otherwise returns a single segment
+func segments(route *entity.Route, split bool) [][]*entity.Location {
+	if !split || len(route.Legs) == 0 {
+		return [][]*entity.Location{route.Polyline}
+	}
+	list := [][]*entity.Location{}
+	for _, leg := range route.Legs {
+		list = append(list, route.Polyline[leg.Start:leg.End+1])
+	}
+	return list
+}
+
+func describe(summary entity.Summary) string {
+	return fmt.Sprintf("Distance: %.0f m, Duration: %.0f s", summary.Distance, summary.Duration)
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/xml"
+	"time"
+
+	"maps.patio.com/entity"
+)
+
+type gpx struct {
+	XMLName   xml.Name    `xml:"gpx"`
+	Xmlns     string      `xml:"xmlns,attr"`
+	Version   string      `xml:"version,attr"`
+	Creator   string      `xml:"creator,attr"`
+	Metadata  gpxMetadata `xml:"metadata"`
+	Waypoints []gpxPoint  `xml:"wpt"`
+	Track     gpxTrack    `xml:"trk"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name"`
+	Desc string `xml:"desc"`
+	Time string `xml:"time"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Name string  `xml:"name,omitempty"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name"`
+	Desc     string       `xml:"desc"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+// GPX returns the route as a GPX 1.1 track with origin and destination waypoints
+func GPX(route *entity.Route, origin *entity.Location, destination *entity.Location, options *Options) ([]byte, error) {
+	doc := gpx{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "maps.patio.com",
+		Metadata: gpxMetadata{
+			Name: options.Name,
+			Desc: describe(route.Summary),
+			Time: time.Now().UTC().Format(time.RFC3339),
+		},
+		Waypoints: []gpxPoint{
+			{Lat: origin.Lat, Lon: origin.Lng, Name: "Origin"},
+			{Lat: destination.Lat, Lon: destination.Lng, Name: "Destination"},
+		},
+		Track: gpxTrack{
+			Name: options.Name,
+			Desc: describe(route.Summary),
+		},
+	}
+
+	for _, segment := range segments(route, options.Segments) {
+		trkseg := gpxSegment{}
+		for _, v := range segment {
+			trkseg.Points = append(trkseg.Points, gpxPoint{Lat: v.Lat, Lon: v.Lng})
+		}
+		doc.Track.Segments = append(doc.Track.Segments, trkseg)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"maps.patio.com/entity"
+)
+
+type kml struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name        string         `xml:"name"`
+	Description string         `xml:"description"`
+	Placemarks  []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string        `xml:"name"`
+	Description string        `xml:"description,omitempty"`
+	Point       *kmlGeometry  `xml:"Point,omitempty"`
+	LineString  *kmlGeometry  `xml:"LineString,omitempty"`
+	Multi       *kmlMultiGeom `xml:"MultiGeometry,omitempty"`
+}
+
+type kmlMultiGeom struct {
+	LineStrings []kmlGeometry `xml:"LineString"`
+}
+
+type kmlGeometry struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+func kmlCoordinates(points []*entity.Location) string {
+	list := []string{}
+	for _, v := range points {
+		list = append(list, fmt.Sprintf("%f,%f", v.Lng, v.Lat))
+	}
+	return strings.Join(list, " ")
+}
+
+// KML returns the route as a KML document with origin and destination placemarks
+func KML(route *entity.Route, origin *entity.Location, destination *entity.Location, options *Options) ([]byte, error) {
+	doc := kml{
+		Xmlns: "http://www.opengis.net/kml/2.2",
+		Document: kmlDocument{
+			Name:        options.Name,
+			Description: describe(route.Summary),
+			Placemarks: []kmlPlacemark{
+				{Name: "Origin", Point: &kmlGeometry{Coordinates: kmlCoordinates([]*entity.Location{origin})}},
+				{Name: "Destination", Point: &kmlGeometry{Coordinates: kmlCoordinates([]*entity.Location{destination})}},
+			},
+		},
+	}
+
+	track := kmlPlacemark{
+		Name:        options.Name,
+		Description: describe(route.Summary),
+	}
+	parts := segments(route, options.Segments)
+	if len(parts) == 1 {
+		track.LineString = &kmlGeometry{Coordinates: kmlCoordinates(parts[0])}
+	} else {
+		track.Multi = &kmlMultiGeom{}
+		for _, v := range parts {
+			track.Multi.LineStrings = append(track.Multi.LineStrings, kmlGeometry{Coordinates: kmlCoordinates(v)})
+		}
+	}
+	doc.Document.Placemarks = append(doc.Document.Placemarks, track)
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
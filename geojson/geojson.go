@@ -0,0 +1,132 @@
+package geojson
+
+import "maps.patio.com/entity"
+
+const ContentType = "application/geo+json"
+
+type Point struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type LineString struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   interface{}            `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Position returns a GeoJSON position, longitude first
+func Position(location *entity.Location) []float64 {
+	return []float64{location.Lng, location.Lat}
+}
+
+func NewPoint(location *entity.Location) *Point {
+	return &Point{
+		Type:        "Point",
+		Coordinates: Position(location),
+	}
+}
+
+func NewLineString(points []*entity.Location) *LineString {
+	coords := [][]float64{}
+	for _, v := range points {
+		coords = append(coords, Position(v))
+	}
+	return &LineString{
+		Type:        "LineString",
+		Coordinates: coords,
+	}
+}
+
+func NewFeature(geometry interface{}, properties map[string]interface{}) *Feature {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	return &Feature{
+		Type:       "Feature",
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
+
+func NewFeatureCollection(features []*Feature) *FeatureCollection {
+	if features == nil {
+		features = []*Feature{}
+	}
+	return &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+// FromAddresses returns a collection of points with the address fields as properties
+func FromAddresses(addresses []*entity.Address) *FeatureCollection {
+	features := []*Feature{}
+	for _, v := range addresses {
+		if v == nil || v.Location == nil {
+			continue
+		}
+		properties := map[string]interface{}{
+			"name":    v.Name,
+			"address": v.Address,
+		}
+		if v.PlaceID != "" {
+			properties["place_id"] = v.PlaceID
+		}
+		if v.Administrative != nil {
+			properties["administrative"] = v.Administrative
+		}
+		features = append(features, NewFeature(NewPoint(v.Location), properties))
+	}
+	return NewFeatureCollection(features)
+}
+
+// FromRoute returns the route as a LineString feature with the summary and
+// the requested steps as properties
+func FromRoute(route *entity.Route) *Feature {
+	properties := map[string]interface{}{
+		"duration": route.Summary.Duration,
+		"distance": route.Summary.Distance,
+	}
+	if route.Summary.TrafficDuration > 0 {
+		properties["traffic_duration"] = route.Summary.TrafficDuration
+	}
+	if len(route.Steps) > 0 {
+		properties["steps"] = route.Steps
+	}
+	return NewFeature(NewLineString(route.Polyline), properties)
+}
+
+// FromRoutes returns the route and its alternatives, the "alternative"
+// property is 0 for the main route and 1.. for the alternatives
+func FromRoutes(route *entity.Route) *FeatureCollection {
+	features := []*Feature{}
+	for i, v := range append([]*entity.Route{route}, route.Alternatives...) {
+		feature := FromRoute(v)
+		feature.Properties["alternative"] = i
+		features = append(features, feature)
+	}
+	return NewFeatureCollection(features)
+}
+
+// FromSummary returns the straight line between origin and destination with the summary as properties
+func FromSummary(origin *entity.Location, destination *entity.Location, summary *entity.Summary) *Feature {
+	properties := map[string]interface{}{
+		"duration": summary.Duration,
+		"distance": summary.Distance,
+	}
+	if summary.TrafficDuration > 0 {
+		properties["traffic_duration"] = summary.TrafficDuration
+	}
+	return NewFeature(NewLineString([]*entity.Location{origin, destination}), properties)
+}
@@ -0,0 +1,100 @@
+package geometry
+
+import (
+	"math"
+
+	"maps.patio.com/entity"
+)
+
+// Cumulative returns the distance in meters from the first point to every point of the polyline
+func Cumulative(points []*entity.Location) []float64 {
+	list := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		list[i] = list[i-1] + Haversine(points[i-1], points[i])
+	}
+	return list
+}
+
+// ProjectSegment snaps p onto the segment a-b, returns the snapped location,
+// the fraction along the segment and the distance in meters from p
+func ProjectSegment(a *entity.Location, b *entity.Location, p *entity.Location) (*entity.Location, float64, float64) {
+	ax, ay := project(p, a)
+	bx, by := project(p, b)
+
+	dx := bx - ax
+	dy := by - ay
+	t := 0.0
+	if dx != 0 || dy != 0 {
+		t = -(ax*dx + ay*dy) / (dx*dx + dy*dy)
+		t = math.Max(0, math.Min(1, t))
+	}
+
+	snapped := &entity.Location{
+		Lat: a.Lat + t*(b.Lat-a.Lat),
+		Lng: a.Lng + t*(b.Lng-a.Lng),
+	}
+	return snapped, t, math.Hypot(ax+t*dx, ay+t*dy)
+}
+
+// Snap returns the closest point of the polyline to p, the distance along the
+// polyline to that point and the distance in meters from p
+func Snap(points []*entity.Location, cumulative []float64, p *entity.Location) (*entity.Location, float64, float64) {
+	if len(points) == 0 {
+		return nil, 0, 0
+	}
+	if len(points) == 1 {
+		return points[0], 0, Haversine(points[0], p)
+	}
+
+	var best *entity.Location
+	bestAlong := 0.0
+	bestDist := math.MaxFloat64
+	for i := 1; i < len(points); i++ {
+		snapped, t, dist := ProjectSegment(points[i-1], points[i], p)
+		if dist < bestDist {
+			best = snapped
+			bestDist = dist
+			bestAlong = cumulative[i-1] + t*(cumulative[i]-cumulative[i-1])
+		}
+	}
+	return best, bestAlong, bestDist
+}
+
+// Interpolate returns the location at the given distance along the polyline
+func Interpolate(points []*entity.Location, cumulative []float64, along float64) *entity.Location {
+	if len(points) == 0 {
+		return nil
+	}
+	if along <= 0 {
+		return points[0]
+	}
+	for i := 1; i < len(points); i++ {
+		if along <= cumulative[i] {
+			segment := cumulative[i] - cumulative[i-1]
+			if segment == 0 {
+				return points[i]
+			}
+			t := (along - cumulative[i-1]) / segment
+			return &entity.Location{
+				Lat: points[i-1].Lat + t*(points[i].Lat-points[i-1].Lat),
+				Lng: points[i-1].Lng + t*(points[i].Lng-points[i-1].Lng),
+			}
+		}
+	}
+	return points[len(points)-1]
+}
+
+// Slice returns the part of the polyline between two distances along it
+func Slice(points []*entity.Location, cumulative []float64, from float64, to float64) []*entity.Location {
+	if len(points) == 0 || to < from {
+		return []*entity.Location{}
+	}
+
+	list := []*entity.Location{Interpolate(points, cumulative, from)}
+	for i, v := range points {
+		if cumulative[i] > from && cumulative[i] < to {
+			list = append(list, v)
+		}
+	}
+	return append(list, Interpolate(points, cumulative, to))
+}
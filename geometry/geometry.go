@@ -0,0 +1,41 @@
+package geometry
+
+import (
+	"math"
+
+	"maps.patio.com/entity"
+)
+
+const EarthRadius = 6371008.8
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Haversine returns the great-circle distance in meters between two locations
+func Haversine(a *entity.Location, b *entity.Location) float64 {
+	lat1 := toRadians(a.Lat)
+	lat2 := toRadians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Length returns the length in meters of a polyline
+func Length(points []*entity.Location) float64 {
+	var total float64
+	for i := 1; i < len(points); i++ {
+		total += Haversine(points[i-1], points[i])
+	}
+	return total
+}
+
+// project converts a location to planar meters around a reference point,
+// precise enough for the short distances found inside a single route
+func project(ref *entity.Location, p *entity.Location) (float64, float64) {
+	x := toRadians(p.Lng-ref.Lng) * math.Cos(toRadians(ref.Lat)) * EarthRadius
+	y := toRadians(p.Lat-ref.Lat) * EarthRadius
+	return x, y
+}
@@ -0,0 +1,55 @@
+package geometry
+
+import (
+	"github.com/heremaps/flexible-polyline/golang/flexpolyline"
+	"github.com/twpayne/go-polyline"
+	"maps.patio.com/entity"
+)
+
+// EncodePolyline encodes the points with the Google encoded polyline algorithm
+func EncodePolyline(points []*entity.Location) string {
+	coords := [][]float64{}
+	for _, v := range points {
+		coords = append(coords, []float64{v.Lat, v.Lng})
+	}
+	return string(polyline.EncodeCoords(coords))
+}
+
+// EncodeFlexPolyline encodes the points with the HERE flexible polyline format
+func EncodeFlexPolyline(points []*entity.Location) (string, error) {
+	coords := []flexpolyline.Point{}
+	for _, v := range points {
+		coords = append(coords, flexpolyline.Point{Lat: v.Lat, Lng: v.Lng})
+	}
+	poly, err := flexpolyline.CreatePolyline(5, coords)
+	if err != nil {
+		return "", err
+	}
+	return poly.Encode()
+}
+
+// DecodePolyline decodes a Google encoded polyline
+func DecodePolyline(encoded string) ([]*entity.Location, error) {
+	coords, _, err := polyline.DecodeCoords([]byte(encoded))
+	if err != nil {
+		return nil, err
+	}
+	list := []*entity.Location{}
+	for _, v := range coords {
+		list = append(list, &entity.Location{Lat: v[0], Lng: v[1]})
+	}
+	return list, nil
+}
+
+// DecodeFlexPolyline decodes a HERE flexible polyline
+func DecodeFlexPolyline(encoded string) ([]*entity.Location, error) {
+	poly, err := flexpolyline.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	list := []*entity.Location{}
+	for _, v := range poly.Coordinates() {
+		list = append(list, &entity.Location{Lat: v.Lat, Lng: v.Lng})
+	}
+	return list, nil
+}
@@ -0,0 +1,94 @@
+package geometry
+
+import (
+	"math"
+
+	"maps.patio.com/entity"
+)
+
+// Simplify reduces a polyline with the Douglas-Peucker algorithm, tolerance in meters
+func Simplify(points []*entity.Location, tolerance float64) []*entity.Location {
+	if len(points) <= 2 || tolerance <= 0 {
+		return points
+	}
+
+	ref := points[0]
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = project(ref, p)
+	}
+
+	keep := make([]bool, len(points))
+	keep[0] = true
+	keep[len(points)-1] = true
+
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		maxDist := 0.0
+		index := -1
+		for i := first + 1; i < last; i++ {
+			d := segmentDistance(xs[i], ys[i], xs[first], ys[first], xs[last], ys[last])
+			if d > maxDist {
+				maxDist = d
+				index = i
+			}
+		}
+
+		if index != -1 && maxDist > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+
+	list := []*entity.Location{}
+	for i, p := range points {
+		if keep[i] {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// Limit simplifies a polyline until it has at most max points, max must be
+// at least 2 as both ends are kept
+func Limit(points []*entity.Location, max int) []*entity.Location {
+	if max <= 0 || len(points) <= max {
+		return points
+	}
+	if max < 2 {
+		max = 2
+	}
+
+	tolerance := 1.0
+	for i := 0; i < 32; i++ {
+		simplified := Simplify(points, tolerance)
+		if len(simplified) <= max {
+			return simplified
+		}
+		tolerance *= 2
+	}
+
+	// Degenerate input, fall back to evenly spaced samples
+	list := []*entity.Location{}
+	step := float64(len(points)-1) / float64(max-1)
+	for i := 0; i < max; i++ {
+		list = append(list, points[int(math.Round(float64(i)*step))])
+	}
+	return list
+}
+
+// segmentDistance returns the distance from (px, py) to the segment (ax, ay)-(bx, by)
+func segmentDistance(px, py, ax, ay, bx, by float64) float64 {
+	dx := bx - ax
+	dy := by - ay
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+	t := ((px-ax)*dx + (py-ay)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
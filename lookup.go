@@ -0,0 +1,298 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/repository"
+)
+
+// lookupFlags are the flags shared by the lookup commands
+type lookupFlags struct {
+	*flag.FlagSet
+	language *string
+	format   *string
+}
+
+func newLookupFlags(name string, usage string, format string) *lookupFlags {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s %s %s\n", os.Args[0], name, usage)
+		flags.PrintDefaults()
+	}
+	return &lookupFlags{
+		FlagSet:  flags,
+		language: flags.String("language", "", "result language, defaults to maps.language"),
+		format:   flags.String("format", format, "output format: table, json or csv"),
+	}
+}
+
+// parse exits with the usage when the arguments or the format are invalid
+func (f *lookupFlags) parse(args []string) {
+	f.Parse(args)
+	if !validFormat(*f.format) {
+		fmt.Fprintf(f.Output(), "invalid format %v\n", *f.format)
+		f.Usage()
+		os.Exit(2)
+	}
+}
+
+// newRepository loads the configuration and the configured provider
+func newRepository(language *string) (*configuration.Configuration, repository.Repository) {
+	config, err := configuration.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *language == "" {
+		*language = config.MAPS.Language
+	}
+	repo, err := repository.New(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config, repo
+}
+
+// parseLocation reads "lat,lng" or "lat lng"
+func parseLocation(value string) (*entity.Location, error) {
+	parts := strings.Fields(strings.ReplaceAll(value, ",", " "))
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid location %q, expected lat,lng", value)
+	}
+	lat, errLat := strconv.ParseFloat(parts[0], 64)
+	lng, errLng := strconv.ParseFloat(parts[1], 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("invalid location %q, expected lat,lng", value)
+	}
+	return &entity.Location{Lat: lat, Lng: lng}, nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+var addressHeader = []string{"name", "address", "lat", "lng", "place_id"}
+
+func addressRow(address *entity.Address) []string {
+	row := []string{address.Name, address.Address, "", "", address.PlaceID}
+	if address.Location != nil {
+		row[2] = formatFloat(address.Location.Lat)
+		row[3] = formatFloat(address.Location.Lng)
+	}
+	return row
+}
+
+func geocode(args []string) {
+	flags := newLookupFlags("geocode", "[flags] address", FORMAT_TABLE)
+	flags.parse(args)
+
+	address := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if address == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	_, repo := newRepository(flags.language)
+	_, place, err := repo.Geocoding(address, *flags.language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := &output{Value: place, Tables: []*table{{Header: addressHeader, Rows: [][]string{addressRow(place)}}}}
+	if err := result.write(os.Stdout, *flags.format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func reverse(args []string) {
+	flags := newLookupFlags("reverse", "-at lat,lng [flags]", FORMAT_TABLE)
+	at := flags.String("at", "", "location as lat,lng")
+	flags.parse(args)
+
+	location, err := parseLocation(*at)
+	if err == nil && flags.NArg() > 0 {
+		err = fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	if err != nil {
+		fmt.Fprintln(flags.Output(), err)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	_, repo := newRepository(flags.language)
+	_, place, err := repo.ReverseGeocoding(location, *flags.language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := &output{Value: place, Tables: []*table{{Header: addressHeader, Rows: [][]string{addressRow(place)}}}}
+	if err := result.write(os.Stdout, *flags.format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// routeFlags are the flags shared by route and distance
+type routeFlags struct {
+	*lookupFlags
+	from      *string
+	to        *string
+	vehicle   *string
+	avoid     *string
+	departure *string
+}
+
+func newRouteFlags(name string) *routeFlags {
+	flags := newLookupFlags(name, "-from lat,lng -to lat,lng [flags]", FORMAT_TABLE)
+	return &routeFlags{
+		lookupFlags: flags,
+		from:        flags.String("from", "", "origin as lat,lng"),
+		to:          flags.String("to", "", "destination as lat,lng"),
+		vehicle:     flags.String("vehicle", "", "configured vehicle profile"),
+		avoid:       flags.String("avoid", "", "comma separated features to avoid: tolls, highways, ferries or unpaved"),
+		departure:   flags.String("departure", "", "departure time for traffic durations, \"now\" or RFC 3339"),
+	}
+}
+
+// locations parses -from and -to, exits with the usage when they are invalid
+func (f *routeFlags) locations() (*entity.Location, *entity.Location) {
+	origin, err := parseLocation(*f.from)
+	if err == nil {
+		var destination *entity.Location
+		destination, err = parseLocation(*f.to)
+		if err == nil {
+			return origin, destination
+		}
+	}
+	fmt.Fprintln(f.Output(), err)
+	f.Usage()
+	os.Exit(2)
+	return nil, nil
+}
+
+func (f *routeFlags) options(config *configuration.Configuration) (*entity.RouteOptions, error) {
+	options := &entity.RouteOptions{}
+
+	if *f.avoid != "" {
+		for _, feature := range strings.Split(*f.avoid, ",") {
+			feature = strings.TrimSpace(feature)
+			found := false
+			for _, v := range entity.AvoidFeatures {
+				if v == feature {
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid avoid feature %v", feature)
+			}
+			options.Avoid = append(options.Avoid, feature)
+		}
+	}
+
+	if *f.departure == "now" {
+		now := time.Now()
+		options.DepartureTime = &now
+	} else if *f.departure != "" {
+		departure, err := time.Parse(time.RFC3339, *f.departure)
+		if err != nil {
+			return nil, fmt.Errorf("invalid departure %v", *f.departure)
+		}
+		options.DepartureTime = &departure
+	}
+
+	if *f.vehicle != "" {
+		vehicles, err := repository.NewVehicles(config)
+		if err != nil {
+			return nil, err
+		}
+		options.Vehicle = vehicles[*f.vehicle]
+		if options.Vehicle == nil {
+			return nil, fmt.Errorf("unknown vehicle %v", *f.vehicle)
+		}
+	}
+	return options, nil
+}
+
+var summaryHeader = []string{"route", "distance_m", "duration_s", "traffic_duration_s"}
+
+func summaryRow(name string, summary *entity.Summary) []string {
+	row := []string{name, formatFloat(summary.Distance), formatFloat(summary.Duration), ""}
+	if summary.TrafficDuration > 0 {
+		row[3] = formatFloat(summary.TrafficDuration)
+	}
+	return row
+}
+
+func route(args []string) {
+	flags := newRouteFlags("route")
+	alternatives := flags.Int("alternatives", 0, "number of alternative routes, at most 3")
+	steps := flags.Bool("steps", false, "list the turn-by-turn maneuvers")
+	flags.parse(args)
+	origin, destination := flags.locations()
+
+	config, repo := newRepository(flags.language)
+	options, err := flags.options(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *alternatives < 0 || *alternatives > 3 {
+		log.Fatalf("invalid alternatives %v", *alternatives)
+	}
+	options.Alternatives = *alternatives
+	options.Steps = *steps
+
+	_, result, err := repo.Route(origin, destination, options, *flags.language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	summaries := &table{Header: summaryHeader, Rows: [][]string{summaryRow("main", &result.Summary)}}
+	for i, v := range result.Alternatives {
+		summaries.Rows = append(summaries.Rows, summaryRow(fmt.Sprintf("alternative %d", i+1), &v.Summary))
+	}
+	tables := []*table{summaries}
+	if *steps {
+		maneuvers := &table{Header: []string{"instruction", "maneuver", "distance_m", "duration_s", "lat", "lng"}}
+		for _, v := range result.Steps {
+			row := []string{v.Instruction, v.Maneuver, formatFloat(v.Distance), formatFloat(v.Duration), "", ""}
+			if v.Location != nil {
+				row[4] = formatFloat(v.Location.Lat)
+				row[5] = formatFloat(v.Location.Lng)
+			}
+			maneuvers.Rows = append(maneuvers.Rows, row)
+		}
+		tables = append(tables, maneuvers)
+	}
+
+	if err := (&output{Value: result, Tables: tables}).write(os.Stdout, *flags.format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func distance(args []string) {
+	flags := newRouteFlags("distance")
+	flags.parse(args)
+	origin, destination := flags.locations()
+
+	config, repo := newRepository(flags.language)
+	options, err := flags.options(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, summary, err := repo.Distance(origin, destination, options, *flags.language)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := &output{Value: summary, Tables: []*table{{Header: summaryHeader, Rows: [][]string{summaryRow("main", summary)}}}}
+	if err := result.write(os.Stdout, *flags.format); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,9 +13,51 @@ import (
 	"maps.patio.com/configuration"
 	"maps.patio.com/repository"
 	routes "maps.patio.com/routes"
+	"maps.patio.com/savedplaces"
 )
 
+// commands are the subcommands of the binary, serve runs when none is given
+var commands = map[string]func(args []string){
+	"serve":    serve,
+	"geocode":  geocode,
+	"reverse":  reverse,
+	"route":    route,
+	"distance": distance,
+	"batch":    batch,
+	"compare":  compareProviders,
+}
+
+const usage = `usage: %s <command> [flags]
+
+commands:
+  serve      start the HTTP server, the default
+  geocode    geocode an address
+  reverse    reverse geocode a lat,lng pair
+  route      route between two lat,lng pairs
+  distance   distance and duration between two lat,lng pairs
+  batch      geocode every row of a CSV
+  compare    compare the configured providers on every row of a CSV
+
+run "%s <command> -h" for the flags of a command
+`
+
 func main() {
+	name, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		name, args = os.Args[1], os.Args[2:]
+	}
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		os.Exit(2)
+	}
+	command(args)
+}
+
+func serve(args []string) {
+	flag.NewFlagSet("serve", flag.ExitOnError).Parse(args)
+
 	ctx := context.Background()
 	serverDoneChan := make(chan os.Signal, 1)
 	signal.Notify(serverDoneChan, os.Interrupt, syscall.SIGTERM)
@@ -29,8 +72,43 @@ func main() {
 		log.Fatal(err)
 	}
 
+	matcher, err := repository.NewMatcher(config, mMap)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	vehicles, err := repository.NewVehicles(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	elevator, err := repository.NewElevation(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	timeZoner, err := repository.NewTimeZone(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	consensus, err := repository.NewConsensus(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store, err := repository.OpenOverrides(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	places, err := savedplaces.Open(config.SAVED_PLACES.Path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	port := fmt.Sprintf(":%d", config.APP.Port)
-	router := routes.Maps(mMap)
+	router := routes.Maps(mMap, matcher, config.MATCH.MaxPoints, store, places, config.ADMIN.Token, config.OVERRIDES.FeedbackToken, config.SAVED_PLACES.Token, config.MAPS.Language, vehicles, elevator, timeZoner, consensus)
 
 	srv := &http.Server{
 		Addr:    port,
@@ -0,0 +1,170 @@
+package matching
+
+import (
+	"math"
+	"sort"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+)
+
+type Options struct {
+	// Sigma is the standard deviation of the GPS noise in meters
+	Sigma float64
+	// Beta weights the difference between route and straight-line distance in meters
+	Beta float64
+	// Radius is the maximum distance in meters between a GPS point and its candidates
+	Radius float64
+	// Candidates is the maximum number of candidates kept per GPS point
+	Candidates int
+	// MinMatched is the share of GPS points that must lie on the route, below
+	// it the trace took a detour and the route distance does not apply
+	MinMatched float64
+}
+
+var DefaultOptions = Options{
+	Sigma:      20,
+	Beta:       50,
+	Radius:     100,
+	Candidates: 8,
+	MinMatched: 0.8,
+}
+
+type candidate struct {
+	location *entity.Location
+	along    float64
+	distance float64
+}
+
+// Match snaps a GPS trace onto a route polyline with a hidden Markov model,
+// candidates are the projections of every GPS point onto the route segments
+// and Viterbi picks the sequence that best agrees with the travelled distance
+func Match(route []*entity.Location, trace []*entity.TracePoint, options Options) *entity.Match {
+	cumulative := geometry.Cumulative(route)
+
+	points := make([]*entity.MatchedPoint, len(trace))
+	layers := [][]*candidate{}
+	indexes := []int{}
+	for i, v := range trace {
+		points[i] = &entity.MatchedPoint{
+			Location: &entity.Location{Lat: v.Lat, Lng: v.Lng},
+		}
+		list := candidates(route, cumulative, points[i].Location, options)
+		if len(list) > 0 {
+			layers = append(layers, list)
+			indexes = append(indexes, i)
+		}
+	}
+
+	match := &entity.Match{
+		Polyline: []*entity.Location{},
+		Points:   points,
+	}
+	if len(trace) > 1 {
+		match.Duration = float64(trace[len(trace)-1].Timestamp - trace[0].Timestamp)
+	}
+	if len(layers) == 0 {
+		return match
+	}
+
+	path := viterbi(layers, indexes, trace, options)
+	for k, c := range path {
+		point := points[indexes[k]]
+		point.Location = c.location
+		point.Matched = true
+		point.Confidence = math.Exp(-0.5 * math.Pow(c.distance/options.Sigma, 2))
+	}
+
+	from := path[0].along
+	to := path[len(path)-1].along
+	match.Distance = math.Max(0, to-from)
+	match.Polyline = geometry.Slice(route, cumulative, from, to)
+	return match
+}
+
+func candidates(route []*entity.Location, cumulative []float64, p *entity.Location, options Options) []*candidate {
+	list := []*candidate{}
+	for i := 1; i < len(route); i++ {
+		snapped, t, dist := geometry.ProjectSegment(route[i-1], route[i], p)
+		if dist > options.Radius {
+			continue
+		}
+		list = append(list, &candidate{
+			location: snapped,
+			along:    cumulative[i-1] + t*(cumulative[i]-cumulative[i-1]),
+			distance: dist,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].distance < list[j].distance
+	})
+	if len(list) > options.Candidates {
+		list = list[:options.Candidates]
+	}
+	return list
+}
+
+func viterbi(layers [][]*candidate, indexes []int, trace []*entity.TracePoint, options Options) []*candidate {
+	score := make([][]float64, len(layers))
+	back := make([][]int, len(layers))
+
+	score[0] = make([]float64, len(layers[0]))
+	for j, c := range layers[0] {
+		score[0][j] = emission(c, options)
+	}
+
+	for k := 1; k < len(layers); k++ {
+		prev := trace[indexes[k-1]]
+		curr := trace[indexes[k]]
+		straight := geometry.Haversine(
+			&entity.Location{Lat: prev.Lat, Lng: prev.Lng},
+			&entity.Location{Lat: curr.Lat, Lng: curr.Lng},
+		)
+
+		score[k] = make([]float64, len(layers[k]))
+		back[k] = make([]int, len(layers[k]))
+		for j, c := range layers[k] {
+			best := math.Inf(-1)
+			for i, p := range layers[k-1] {
+				s := score[k-1][i] + transition(p, c, straight, options)
+				if s > best {
+					best = s
+					back[k][j] = i
+				}
+			}
+			score[k][j] = best + emission(c, options)
+		}
+	}
+
+	last := len(layers) - 1
+	bestIndex := 0
+	for j := range score[last] {
+		if score[last][j] > score[last][bestIndex] {
+			bestIndex = j
+		}
+	}
+
+	path := make([]*candidate, len(layers))
+	for k := last; k >= 0; k-- {
+		path[k] = layers[k][bestIndex]
+		if k > 0 {
+			bestIndex = back[k][bestIndex]
+		}
+	}
+	return path
+}
+
+// emission is the log probability of observing the GPS point from the candidate
+func emission(c *candidate, options Options) float64 {
+	return -0.5 * math.Pow(c.distance/options.Sigma, 2)
+}
+
+// transition is the log probability of moving between two candidates, couriers
+// follow the route forward so going backwards is heavily penalized
+func transition(from *candidate, to *candidate, straight float64, options Options) float64 {
+	routed := to.along - from.along
+	if routed < -options.Sigma {
+		return -math.Abs(routed) / options.Beta * 10
+	}
+	return -math.Abs(math.Abs(routed)-straight) / options.Beta
+}
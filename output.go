@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FORMAT_TABLE = "table"
+	FORMAT_JSON  = "json"
+	FORMAT_CSV   = "csv"
+)
+
+func validFormat(format string) bool {
+	return format == FORMAT_TABLE || format == FORMAT_JSON || format == FORMAT_CSV
+}
+
+type table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// output is the result of a command, Value is written as JSON and Tables as
+// aligned columns or CSV, tables are separated by an empty line
+type output struct {
+	Value  interface{}
+	Tables []*table
+}
+
+func (o *output) write(w io.Writer, format string) error {
+	switch format {
+	case FORMAT_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(o.Value)
+	case FORMAT_CSV:
+		writer := csv.NewWriter(w)
+		for i, t := range o.Tables {
+			if i > 0 {
+				writer.Write(nil)
+			}
+			writer.Write(t.Header)
+			writer.WriteAll(t.Rows)
+		}
+		writer.Flush()
+		return writer.Error()
+	case FORMAT_TABLE:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for i, t := range o.Tables {
+			if i > 0 {
+				fmt.Fprintln(tw)
+			}
+			fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+			for _, row := range t.Rows {
+				fmt.Fprintln(tw, strings.Join(row, "\t"))
+			}
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format %v", format)
+	}
+}
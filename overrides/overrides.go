@@ -0,0 +1,286 @@
+// Package overrides stores the locations pinned by ops staff and the
+// feedback queue they review, persisted as a JSON file
+package overrides
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"maps.patio.com/addrparse"
+	"maps.patio.com/entity"
+	"maps.patio.com/filestore"
+	"maps.patio.com/geometry"
+)
+
+var ErrNotFound = errors.New("not found")
+var ErrConflict = errors.New("another override has the same address")
+var ErrFull = errors.New("too much feedback pending review")
+
+// MAX_PENDING_FEEDBACK caps the review queue, reports are refused until ops
+// catch up
+const MAX_PENDING_FEEDBACK = 1000
+
+type data struct {
+	Overrides []*entity.Override `json:"overrides"`
+	Feedback  []*entity.Feedback `json:"feedback"`
+}
+
+type Store struct {
+	mu        sync.RWMutex
+	path      string
+	overrides map[string]*entity.Override
+	feedback  map[string]*entity.Feedback
+}
+
+// Open loads the store from path, an empty path keeps everything in memory
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		overrides: map[string]*entity.Override{},
+		feedback:  map[string]*entity.Feedback{},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	var d data
+	if err := filestore.Read(path, &d); err != nil {
+		return nil, err
+	}
+	for _, v := range d.Overrides {
+		s.overrides[v.ID] = v
+	}
+	for _, v := range d.Feedback {
+		s.feedback[v.ID] = v
+	}
+	return s, nil
+}
+
+// Lookup returns the override for the address, matched by its canonical form,
+// addresses without one never match
+func (s *Store) Lookup(address string) (*entity.Override, bool) {
+	key := addrparse.Key(address)
+	if key == "" {
+		return nil, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.overrides {
+		if v.Key == key {
+			return copyOverride(v), true
+		}
+	}
+	return nil, false
+}
+
+func (s *Store) List() []*entity.Override {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []*entity.Override{}
+	for _, v := range s.overrides {
+		list = append(list, copyOverride(v))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+func (s *Store) Get(id string) (*entity.Override, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.overrides[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyOverride(v), nil
+}
+
+// Save creates the override, or replaces the one with the same ID or the same
+// canonical query, replacing by ID fails when another override has the new query
+func (s *Store) Save(override *entity.Override) (*entity.Override, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.save(override); err != nil {
+		return nil, err
+	}
+	return copyOverride(override), s.persist()
+}
+
+// save stores the override in memory, callers hold the lock
+func (s *Store) save(override *entity.Override) error {
+	now := time.Now().UTC()
+	override.Key = addrparse.Key(override.Query)
+	if override.ID == "" {
+		for _, v := range s.overrides {
+			if v.Key == override.Key {
+				override.ID = v.ID
+				override.CreatedAt = v.CreatedAt
+			}
+		}
+	} else if v, ok := s.overrides[override.ID]; ok {
+		for _, other := range s.overrides {
+			if other.ID != override.ID && other.Key == override.Key {
+				return ErrConflict
+			}
+		}
+		override.CreatedAt = v.CreatedAt
+	} else {
+		return ErrNotFound
+	}
+	if override.ID == "" {
+		override.ID = filestore.NewID()
+		override.CreatedAt = now
+	}
+	override.UpdatedAt = now
+
+	s.overrides[override.ID] = copyOverride(override)
+	return nil
+}
+
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.overrides[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.overrides, id)
+	return s.persist()
+}
+
+// AddFeedback queues a correction for review
+func (s *Store) AddFeedback(feedback *entity.Feedback) (*entity.Feedback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := 0
+	for _, v := range s.feedback {
+		if v.Status == entity.FEEDBACK_PENDING {
+			pending++
+		}
+	}
+	if pending >= MAX_PENDING_FEEDBACK {
+		return nil, ErrFull
+	}
+
+	feedback.ID = filestore.NewID()
+	feedback.Status = entity.FEEDBACK_PENDING
+	feedback.CreatedAt = time.Now().UTC()
+	if feedback.Returned != nil && feedback.Delivered != nil {
+		feedback.Distance = geometry.Haversine(feedback.Returned, feedback.Delivered)
+	}
+
+	s.feedback[feedback.ID] = copyFeedback(feedback)
+	return feedback, s.persist()
+}
+
+// Feedback lists the feedback with the given status, all of it when status is empty
+func (s *Store) Feedback(status string) []*entity.Feedback {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []*entity.Feedback{}
+	for _, v := range s.feedback {
+		if status == "" || v.Status == status {
+			list = append(list, copyFeedback(v))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Review approves or rejects a pending feedback, approving it pins the query
+// to the delivered location unless override carries a different one
+func (s *Store) Review(id string, approve bool, override *entity.Override) (*entity.Feedback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feedback, ok := s.feedback[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if feedback.Status != entity.FEEDBACK_PENDING {
+		return nil, errors.New("feedback already reviewed")
+	}
+
+	if !approve {
+		feedback.Status = entity.FEEDBACK_REJECTED
+		return copyFeedback(feedback), s.persist()
+	}
+
+	if override == nil {
+		override = &entity.Override{}
+	}
+	override.ID = ""
+	if override.Query == "" {
+		override.Query = feedback.Query
+	}
+	if override.Location == nil {
+		override.Location = feedback.Delivered
+	}
+	if override.Address == "" {
+		override.Address = override.Query
+	}
+	if override.Name == "" {
+		override.Name = override.Query
+	}
+	if err := s.save(override); err != nil {
+		return nil, err
+	}
+
+	feedback.OverrideID = override.ID
+	feedback.Status = entity.FEEDBACK_APPROVED
+	return copyFeedback(feedback), s.persist()
+}
+
+// copyOverride and copyFeedback keep the stored values private, callers
+// encode them after the lock is released while a review may change them
+func copyOverride(override *entity.Override) *entity.Override {
+	result := *override
+	result.Location = copyLocation(override.Location)
+	return &result
+}
+
+func copyFeedback(feedback *entity.Feedback) *entity.Feedback {
+	result := *feedback
+	result.Returned = copyLocation(feedback.Returned)
+	result.Delivered = copyLocation(feedback.Delivered)
+	return &result
+}
+
+func copyLocation(location *entity.Location) *entity.Location {
+	if location == nil {
+		return nil
+	}
+	result := *location
+	return &result
+}
+
+// persist writes the store to disk, callers hold the lock
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	d := data{
+		Overrides: []*entity.Override{},
+		Feedback:  []*entity.Feedback{},
+	}
+	for _, v := range s.overrides {
+		d.Overrides = append(d.Overrides, v)
+	}
+	for _, v := range s.feedback {
+		d.Feedback = append(d.Feedback, v)
+	}
+
+	return filestore.Write(s.path, d)
+}
@@ -0,0 +1,52 @@
+// Package pagination builds the opaque cursors returned by paginated endpoints,
+// a cursor records the provider and the page it came from so it can only be
+// replayed against the same provider and query
+package pagination
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Cursor struct {
+	Provider string `json:"p"`
+	Query    string `json:"q"`
+	Token    string `json:"t,omitempty"`
+	Offset   int    `json:"o,omitempty"`
+}
+
+// QueryHash identifies the query without storing it in the cursor
+func QueryHash(query string) string {
+	sum := sha1.Sum([]byte(query))
+	return hex.EncodeToString(sum[:4])
+}
+
+func Encode(cursor *Cursor) string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// Decode returns the cursor, an empty text is the first page
+func Decode(text string, provider string, query string) (*Cursor, error) {
+	if text == "" {
+		return &Cursor{Provider: provider, Query: QueryHash(query)}, nil
+	}
+
+	content, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	cursor := &Cursor{}
+	if err := json.Unmarshal(content, cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Provider != provider || cursor.Query != QueryHash(query) || cursor.Offset < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,292 @@
+// Package pluscode implements Open Location Code encoding and decoding,
+// following the reference implementation at github.com/google/open-location-code
+package pluscode
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+
+	"maps.patio.com/entity"
+)
+
+const (
+	Separator = '+'
+	Padding   = '0'
+	Alphabet  = "23456789CFGHJMPQRVWX"
+
+	DefaultLength = 10
+
+	sepPos        = 8
+	encBase       = 20
+	maxDigitCount = 15
+	pairCodeLen   = 10
+	gridCodeLen   = maxDigitCount - pairCodeLen
+	gridCols      = 4
+	gridRows      = 5
+	latMax        = 90
+	lngMax        = 180
+
+	pairFirstPlaceValue = 160000               // encBase^(pairCodeLen/2 - 1)
+	pairPrecision       = 8000                 // encBase^3
+	gridLatFirstValue   = 625                  // gridRows^(gridCodeLen - 1)
+	gridLngFirstValue   = 256                  // gridCols^(gridCodeLen - 1)
+	finalLatPrecision   = pairPrecision * 3125 // gridRows^gridCodeLen
+	finalLngPrecision   = pairPrecision * 1024 // gridCols^gridCodeLen
+)
+
+var ErrInvalidCode = errors.New("invalid plus code")
+
+// CodeArea is the rectangle covered by a code
+type CodeArea struct {
+	LatLo, LngLo, LatHi, LngHi float64
+	Len                        int
+}
+
+func (c CodeArea) Center() (float64, float64) {
+	lat := math.Min(c.LatLo+(c.LatHi-c.LatLo)/2, latMax)
+	lng := math.Min(c.LngLo+(c.LngHi-c.LngLo)/2, lngMax)
+	return lat, lng
+}
+
+// IsValid reports whether the code is a valid full or short code
+func IsValid(code string) bool {
+	if code == "" {
+		return false
+	}
+	code = strings.ToUpper(code)
+
+	sep := strings.IndexByte(code, Separator)
+	if sep == -1 || sep != strings.LastIndexByte(code, Separator) || sep > sepPos || sep%2 == 1 {
+		return false
+	}
+
+	if pad := strings.IndexByte(code, Padding); pad >= 0 {
+		if sep < sepPos || pad == 0 || pad%2 == 1 || len(code) > sep+1 {
+			return false
+		}
+		if strings.Trim(code[pad:sep], string(Padding)) != "" {
+			return false
+		}
+	}
+
+	if len(code)-sep-1 == 1 {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c != Separator && c != Padding && strings.IndexByte(Alphabet, c) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsShort reports whether the code is a valid short code, missing leading digits
+func IsShort(code string) bool {
+	return IsValid(code) && strings.IndexByte(code, Separator) < sepPos
+}
+
+// IsFull reports whether the code is a valid full code
+func IsFull(code string) bool {
+	if !IsValid(code) || IsShort(code) {
+		return false
+	}
+	code = strings.ToUpper(code)
+	if strings.IndexByte(Alphabet, code[0])*encBase >= latMax*2 {
+		return false
+	}
+	if len(code) > 1 && code[1] != Separator && strings.IndexByte(Alphabet, code[1])*encBase >= lngMax*2 {
+		return false
+	}
+	return true
+}
+
+// Encode returns the code of the given length for a location, length is
+// 2, 4, 6, 8 or between 10 and 15
+func Encode(lat, lng float64, length int) (string, error) {
+	if length < 2 || (length < pairCodeLen && length%2 == 1) {
+		return "", errors.New("invalid plus code length")
+	}
+	if length > maxDigitCount {
+		length = maxDigitCount
+	}
+
+	lat = math.Max(-latMax, math.Min(latMax, lat))
+	lng = normalizeLng(lng)
+	if lat == latMax {
+		lat -= latPrecision(length)
+	}
+
+	latVal := int64(math.Floor(math.Round((lat+latMax)*finalLatPrecision*1e6) / 1e6))
+	lngVal := int64(math.Floor(math.Round((lng+lngMax)*finalLngPrecision*1e6) / 1e6))
+
+	digits := make([]byte, maxDigitCount)
+	for i := maxDigitCount - 1; i >= pairCodeLen; i-- {
+		digits[i] = Alphabet[(latVal%gridRows)*gridCols+lngVal%gridCols]
+		latVal /= gridRows
+		lngVal /= gridCols
+	}
+	for i := pairCodeLen/2 - 1; i >= 0; i-- {
+		digits[2*i] = Alphabet[latVal%encBase]
+		digits[2*i+1] = Alphabet[lngVal%encBase]
+		latVal /= encBase
+		lngVal /= encBase
+	}
+
+	code := string(digits[:length])
+	if length < sepPos {
+		code += strings.Repeat(string(Padding), sepPos-length)
+	}
+	return code[:sepPos] + string(Separator) + code[sepPos:], nil
+}
+
+// Decode returns the area covered by a full code
+func Decode(code string) (CodeArea, error) {
+	if !IsFull(code) {
+		return CodeArea{}, ErrInvalidCode
+	}
+	code = strings.ToUpper(code)
+	code = strings.Replace(code, string(Separator), "", 1)
+	code = strings.TrimRight(code, string(Padding))
+	if len(code) > maxDigitCount {
+		code = code[:maxDigitCount]
+	}
+
+	normalLat := int64(-latMax * pairPrecision)
+	normalLng := int64(-lngMax * pairPrecision)
+	var gridLat, gridLng int64
+
+	digits := len(code)
+	if digits > pairCodeLen {
+		digits = pairCodeLen
+	}
+	var pv int64 = pairFirstPlaceValue
+	for i := 0; i < digits; i += 2 {
+		normalLat += int64(strings.IndexByte(Alphabet, code[i])) * pv
+		normalLng += int64(strings.IndexByte(Alphabet, code[i+1])) * pv
+		if i < digits-2 {
+			pv /= encBase
+		}
+	}
+	latPrec := float64(pv) / pairPrecision
+	lngPrec := float64(pv) / pairPrecision
+
+	if len(code) > pairCodeLen {
+		var rowpv int64 = gridLatFirstValue
+		var colpv int64 = gridLngFirstValue
+		for i := pairCodeLen; i < len(code); i++ {
+			value := int64(strings.IndexByte(Alphabet, code[i]))
+			gridLat += (value / gridCols) * rowpv
+			gridLng += (value % gridCols) * colpv
+			if i < len(code)-1 {
+				rowpv /= gridRows
+				colpv /= gridCols
+			}
+		}
+		latPrec = float64(rowpv) / finalLatPrecision
+		lngPrec = float64(colpv) / finalLngPrecision
+	}
+
+	lat := float64(normalLat)/pairPrecision + float64(gridLat)/finalLatPrecision
+	lng := float64(normalLng)/pairPrecision + float64(gridLng)/finalLngPrecision
+	return CodeArea{
+		LatLo: lat,
+		LngLo: lng,
+		LatHi: lat + latPrec,
+		LngHi: lng + lngPrec,
+		Len:   len(code),
+	}, nil
+}
+
+// RecoverNearest returns the full code closest to the reference location
+// that matches the short code
+func RecoverNearest(code string, refLat, refLng float64) (string, error) {
+	if !IsShort(code) {
+		if IsFull(code) {
+			return strings.ToUpper(code), nil
+		}
+		return "", ErrInvalidCode
+	}
+	code = strings.ToUpper(code)
+	refLat = math.Max(-latMax, math.Min(latMax, refLat))
+	refLng = normalizeLng(refLng)
+
+	paddingLen := sepPos - strings.IndexByte(code, Separator)
+	resolution := math.Pow(encBase, float64(2-paddingLen/2))
+	half := resolution / 2
+
+	ref, err := Encode(refLat, refLng, pairCodeLen)
+	if err != nil {
+		return "", err
+	}
+	area, err := Decode(ref[:paddingLen] + code)
+	if err != nil {
+		return "", err
+	}
+
+	lat, lng := area.Center()
+	if refLat+half < lat && lat-resolution >= -latMax {
+		lat -= resolution
+	} else if refLat-half > lat && lat+resolution <= latMax {
+		lat += resolution
+	}
+	if refLng+half < lng {
+		lng -= resolution
+	} else if refLng-half > lng {
+		lng += resolution
+	}
+	return Encode(lat, lng, area.Len)
+}
+
+func latPrecision(length int) float64 {
+	if length <= pairCodeLen {
+		return math.Pow(encBase, math.Floor(float64(length)/-2+2))
+	}
+	return math.Pow(encBase, -3) / math.Pow(gridRows, float64(length-pairCodeLen))
+}
+
+func normalizeLng(lng float64) float64 {
+	for lng < -lngMax {
+		lng += 2 * lngMax
+	}
+	for lng >= lngMax {
+		lng -= 2 * lngMax
+	}
+	return lng
+}
+
+// ToEntity returns the code with its area as an entity.PlusCode
+func ToEntity(code string) (*entity.PlusCode, error) {
+	area, err := Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	lat, lng := area.Center()
+	return &entity.PlusCode{
+		Code:      strings.ToUpper(code),
+		Location:  &entity.Location{Lat: lat, Lng: lng},
+		Southwest: &entity.Location{Lat: area.LatLo, Lng: area.LngLo},
+		Northeast: &entity.Location{Lat: area.LatHi, Lng: area.LngHi},
+		Length:    area.Len,
+	}, nil
+}
+
+var codePattern = regexp.MustCompile(`(?i)(^|[\s,])([23456789CFGHJMPQRVWX0]{2,8}\+[23456789CFGHJMPQRVWX]*)($|[\s,])`)
+
+// Find looks for a plus code inside free text, returns the code and the rest
+// of the text, usually the reference locality of a short code
+func Find(text string) (string, string, bool) {
+	match := codePattern.FindStringSubmatchIndex(text)
+	if match == nil {
+		return "", "", false
+	}
+	code := text[match[4]:match[5]]
+	if !IsValid(code) {
+		return "", "", false
+	}
+	rest := strings.Trim(text[:match[4]]+" "+text[match[5]:], " ,")
+	return strings.ToUpper(code), rest, true
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"maps.patio.com/addrparse"
+	"maps.patio.com/cache"
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+// Cached keeps successful geocoding results keyed by the language and the
+// canonical address, labels differ from one language to another, addresses
+// without a canonical form are not cached as they would share the same key
+type Cached struct {
+	Repository
+	Cache *cache.Cache
+}
+
+func (c *Cached) Geocoding(address string, language string) (string, *entity.Address, error) {
+	canonical := addrparse.Key(address)
+	if canonical == "" {
+		return c.Repository.Geocoding(address, language)
+	}
+
+	key := "geocoding:" + language + ":" + canonical
+	if v, ok := c.Cache.Get(key); ok {
+		result := *v.(*entity.Address)
+		return status.OK, &result, nil
+	}
+
+	statusMaps, result, err := c.Repository.Geocoding(address, language)
+	if err == nil {
+		c.Cache.Set(key, result)
+	}
+	return statusMaps, result, err
+}
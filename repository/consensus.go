@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	status "maps.patio.com/responses"
+)
+
+// DEFAULT_CONSENSUS_THRESHOLD is used when the configuration has no threshold, in meters
+const DEFAULT_CONSENSUS_THRESHOLD = 500
+
+// Consensus geocodes with every provider in parallel and merges the answers
+type Consensus struct {
+	Repositories []Repository
+	Threshold    float64
+}
+
+func (c *Consensus) Geocoding(address string, language string) (string, *entity.Consensus, error) {
+	results := make([]*entity.ProviderResult, len(c.Repositories))
+	var wg sync.WaitGroup
+	for i, repo := range c.Repositories {
+		wg.Add(1)
+		go func(i int, repo Repository) {
+			defer wg.Done()
+			statusMaps, place, err := repo.Geocoding(address, language)
+			result := &entity.ProviderResult{Provider: repo.Provider(), Status: statusMaps}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Address = place
+			}
+			results[i] = result
+		}(i, repo)
+	}
+	wg.Wait()
+
+	return merge(results, c.Threshold)
+}
+
+// merge takes the largest group of answers within the threshold of one of
+// them, the consensus location is the centroid of that group
+func merge(results []*entity.ProviderResult, threshold float64) (string, *entity.Consensus, error) {
+	answered := []*entity.ProviderResult{}
+	for _, v := range results {
+		if v.Address != nil && v.Address.Location != nil {
+			answered = append(answered, v)
+		}
+	}
+	if len(answered) == 0 {
+		for _, v := range results {
+			if v.Error != "" {
+				return v.Status, nil, errors.New(v.Error)
+			}
+		}
+		return status.ZERO_RESULTS, nil, errors.New("no provider found the address")
+	}
+
+	consensus := &entity.Consensus{Results: results}
+	var group []*entity.ProviderResult
+	bestSpread := 0.0
+	for _, a := range answered {
+		members := []*entity.ProviderResult{}
+		spread := 0.0
+		for _, b := range answered {
+			distance := geometry.Haversine(a.Address.Location, b.Address.Location)
+			if distance > consensus.MaxDistance {
+				consensus.MaxDistance = distance
+			}
+			if distance <= threshold {
+				members = append(members, b)
+				spread += distance
+			}
+		}
+		if len(members) > len(group) || (len(members) == len(group) && spread < bestSpread) {
+			group, bestSpread = members, spread
+		}
+	}
+
+	centroid := &entity.Location{}
+	for _, v := range group {
+		centroid.Lat += v.Address.Location.Lat / float64(len(group))
+		centroid.Lng += v.Address.Location.Lng / float64(len(group))
+	}
+
+	var closest *entity.ProviderResult
+	for _, v := range answered {
+		v.Distance = geometry.Haversine(centroid, v.Address.Location)
+		if v.Distance > threshold {
+			consensus.Disagreement = true
+		}
+	}
+	for _, v := range group {
+		if closest == nil || v.Distance < closest.Distance {
+			closest = v
+		}
+	}
+
+	merged := *closest.Address
+	merged.Location = centroid
+	consensus.Address = &merged
+	consensus.Confidence = float64(len(group)) / float64(len(results))
+	consensus.NeedsReview = consensus.Disagreement || len(answered) < 2
+
+	return status.OK, consensus, nil
+}
+
+// NewConsensus returns nil when no consensus providers are configured
+func NewConsensus(config *configuration.Configuration) (*Consensus, error) {
+	if len(config.CONSENSUS.Providers) == 0 {
+		return nil, nil
+	}
+	if len(config.CONSENSUS.Providers) < 2 {
+		return nil, errors.New("consensus needs at least two providers")
+	}
+
+	consensus := &Consensus{Threshold: config.CONSENSUS.Threshold}
+	if consensus.Threshold <= 0 {
+		consensus.Threshold = DEFAULT_CONSENSUS_THRESHOLD
+	}
+	for _, maps := range config.CONSENSUS.Providers {
+		repo, err := NewProvider(config, maps)
+		if err != nil {
+			return nil, err
+		}
+		consensus.Repositories = append(consensus.Repositories, repo)
+	}
+	return consensus, nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"maps.patio.com/coordinates"
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+// Coordinates answers addresses that are really coordinates or map links by
+// reverse geocoding the point they contain, the pin the customer sent is kept
+type Coordinates struct {
+	Repository
+}
+
+func (c *Coordinates) Geocoding(address string, language string) (string, *entity.Address, error) {
+	location, ok := coordinates.Parse(address)
+	if !ok {
+		return c.Repository.Geocoding(address, language)
+	}
+
+	statusMaps, result, err := c.Repository.ReverseGeocoding(location, language)
+	if err != nil {
+		return statusMaps, nil, err
+	}
+	result.Location = location
+
+	return status.OK, result, nil
+}
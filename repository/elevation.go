@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"fmt"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/repository/googlemaps"
+	"maps.patio.com/repository/srtm"
+)
+
+type Elevator interface {
+	Elevation(points []*entity.Location) (status string, elevations []float64, err error)
+}
+
+// NewElevation returns nil when no elevation provider is configured
+func NewElevation(config *configuration.Configuration) (Elevator, error) {
+	var elevator Elevator
+	var err error
+
+	switch config.ELEVATION.Provider {
+	case "":
+	case "google_maps":
+		elevator = googlemaps.New(config.MAPS.ApiKey)
+		if config.MAPS.Provider != "google_maps" {
+			err = fmt.Errorf("elevation provider %v requires maps provider google_maps", config.ELEVATION.Provider)
+		}
+	case "local":
+		elevator = srtm.New(config.ELEVATION.Path)
+		if config.ELEVATION.Path == "" {
+			err = fmt.Errorf("elevation provider %v requires the path of the .hgt tiles", config.ELEVATION.Provider)
+		}
+	default:
+		err = fmt.Errorf("invalid elevation engine %v", config.ELEVATION.Provider)
+	}
+
+	return elevator, err
+}
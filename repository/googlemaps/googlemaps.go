@@ -4,14 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/twpayne/go-polyline"
+	"maps.patio.com/addrparse"
 	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+	"maps.patio.com/pagination"
 	status "maps.patio.com/responses"
+	"maps.patio.com/timezone"
 )
 
 type GoogleMaps struct {
@@ -19,18 +27,59 @@ type GoogleMaps struct {
 }
 
 type Results struct {
-	Results      []ResultItem `json:"results"`
-	Status       string       `json:"status"`
-	ErrorMessage string       `json:"error_message"`
+	Results       []ResultItem `json:"results"`
+	Status        string       `json:"status"`
+	ErrorMessage  string       `json:"error_message"`
+	NextPageToken string       `json:"next_page_token"`
 }
 
 type ResultItem struct {
-	ResultItem Geometry `json:"geometry"`
-	Address    string   `json:"formatted_address"`
+	ResultItem        Geometry                   `json:"geometry"`
+	Address           string                     `json:"formatted_address"`
+	PlaceID           string                     `json:"place_id"`
+	AddressComponents []*entity.AddressComponent `json:"address_components"`
+}
+
+// administrative maps the component types to the hierarchy, in Bolivia the
+// municipality comes as administrative_area_level_3 or as the locality
+func administrative(components []*entity.AddressComponent) *entity.Administrative {
+	admin := &entity.Administrative{}
+	for _, component := range components {
+		for _, kind := range component.Types {
+			switch kind {
+			case "country":
+				admin.Country = component.LongName
+				admin.CountryCode = component.ShortName
+			case "administrative_area_level_1":
+				admin.Department = component.LongName
+			case "administrative_area_level_2":
+				admin.Province = component.LongName
+			case "administrative_area_level_3":
+				admin.Municipality = component.LongName
+			case "locality":
+				if admin.Municipality == "" {
+					admin.Municipality = component.LongName
+				}
+			case "sublocality", "sublocality_level_1":
+				admin.District = component.LongName
+			case "neighborhood":
+				admin.Neighbourhood = component.LongName
+			case "postal_code":
+				admin.PostalCode = component.LongName
+			}
+		}
+	}
+	return admin
 }
 
 type Geometry struct {
 	Location entity.Location `json:"location"`
+	Viewport *Viewport       `json:"viewport"`
+}
+
+type Viewport struct {
+	Northeast entity.Location `json:"northeast"`
+	Southwest entity.Location `json:"southwest"`
 }
 
 type Response struct {
@@ -44,9 +93,10 @@ type Row struct {
 }
 
 type Element struct {
-	Distance       ValueFloat `json:"distance"`
-	Duration       ValueFloat `json:"duration"`
-	StatusDistance string     `json:"Status"`
+	Distance          ValueFloat  `json:"distance"`
+	Duration          ValueFloat  `json:"duration"`
+	DurationInTraffic *ValueFloat `json:"duration_in_traffic"`
+	StatusDistance    string      `json:"Status"`
 }
 
 type ResponseRoute struct {
@@ -60,8 +110,43 @@ type Route struct {
 }
 
 type Leg struct {
-	Distance ValueFloat `json:"distance"`
-	Duration ValueFloat `json:"duration"`
+	Distance          ValueFloat  `json:"distance"`
+	Duration          ValueFloat  `json:"duration"`
+	DurationInTraffic *ValueFloat `json:"duration_in_traffic"`
+	Steps             []Step      `json:"steps"`
+}
+
+type Step struct {
+	HTMLInstructions string          `json:"html_instructions"`
+	Maneuver         string          `json:"maneuver"`
+	Distance         ValueFloat      `json:"distance"`
+	Duration         ValueFloat      `json:"duration"`
+	StartLocation    entity.Location `json:"start_location"`
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// plainText removes the markup Google puts in the instructions
+func plainText(instructions string) string {
+	text := html.UnescapeString(htmlTags.ReplaceAllString(instructions, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func toSteps(legs []Leg) []*entity.Step {
+	steps := []*entity.Step{}
+	for _, leg := range legs {
+		for _, v := range leg.Steps {
+			location := v.StartLocation
+			steps = append(steps, &entity.Step{
+				Instruction: plainText(v.HTMLInstructions),
+				Maneuver:    v.Maneuver,
+				Distance:    v.Distance.Value,
+				Duration:    v.Duration.Value,
+				Location:    &location,
+			})
+		}
+	}
+	return steps
 }
 
 type OverviewPolyline struct {
@@ -82,10 +167,34 @@ func (g *GoogleMaps) Provider() string {
 	return "GOOGLE MAPS"
 }
 
-func (g *GoogleMaps) Geocoding(address string) (string, *entity.Address, error) {
+func (g *GoogleMaps) Geocoding(address string, language string) (string, *entity.Address, error) {
 
 	params := url.Values{}
 	params.Add("address", address)
+
+	return g.geocode(params, address, language)
+}
+
+// GeocodingStructured sends the street part as the address and filters by city
+func (g *GoogleMaps) GeocodingStructured(parsed *entity.ParsedAddress, language string) (string, *entity.Address, error) {
+	address := addrparse.Street(parsed)
+	if address == "" {
+		address = parsed.City
+	}
+
+	params := url.Values{}
+	params.Add("address", address)
+	if parsed.City != "" {
+		params.Add("components", "country:BO|locality:"+parsed.City)
+	}
+
+	return g.geocode(params, address, language)
+}
+
+func (g *GoogleMaps) geocode(params url.Values, address string, language string) (string, *entity.Address, error) {
+	if language != "" {
+		params.Add("language", language)
+	}
 	params.Add("key", g.ApiKey)
 
 	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/geocode/json?%s", params.Encode())
@@ -114,6 +223,7 @@ func (g *GoogleMaps) Geocoding(address string) (string, *entity.Address, error)
 		return results.Status, nil, errors.New("No results for " + address)
 	} else {
 		newAddress := &entity.Address{
+			PlaceID:  results.Results[0].PlaceID,
 			Name:     strings.Split(results.Results[0].Address, ",")[0],
 			Address:  results.Results[0].Address,
 			Location: &results.Results[0].ResultItem.Location,
@@ -122,10 +232,13 @@ func (g *GoogleMaps) Geocoding(address string) (string, *entity.Address, error)
 	}
 }
 
-func (g *GoogleMaps) ReverseGeocoding(location *entity.Location) (string, *entity.Address, error) {
+func (g *GoogleMaps) ReverseGeocoding(location *entity.Location, language string) (string, *entity.Address, error) {
 	latlng := fmt.Sprintf("%f,%f", location.Lat, location.Lng)
 	params := url.Values{}
 	params.Add("latlng", latlng)
+	if language != "" {
+		params.Add("language", language)
+	}
 	params.Add("key", g.ApiKey)
 
 	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/geocode/json?%s", params.Encode())
@@ -153,69 +266,181 @@ func (g *GoogleMaps) ReverseGeocoding(location *entity.Location) (string, *entit
 		return results.Status, nil, errors.New("No results for " + latlng)
 	} else {
 		address := &entity.Address{
-			Address:  results.Results[0].Address,
-			Name:     strings.Split(results.Results[0].Address, ",")[0],
-			Location: &results.Results[0].ResultItem.Location,
+			PlaceID:        results.Results[0].PlaceID,
+			Address:        results.Results[0].Address,
+			Name:           strings.Split(results.Results[0].Address, ",")[0],
+			Location:       &results.Results[0].ResultItem.Location,
+			Administrative: administrative(results.Results[0].AddressComponents),
 		}
 		return status.OK, address, nil
 	}
 }
 
-func (g *GoogleMaps) Search(address string, location *entity.Location) (string, []*entity.Address, error) {
+// Search pages through Google results, the cursor keeps the page token and how
+// many results of that page were already returned when limit cuts a page short
+func (g *GoogleMaps) Search(address string, location *entity.Location, limit int, cursor string, language string) (string, []*entity.Address, string, error) {
+	page, err := pagination.Decode(cursor, g.Provider(), address)
+	if err != nil {
+		return status.INVALID_DATA, nil, "", err
+	}
+
 	latlng := fmt.Sprintf("%f,%f", location.Lat, location.Lng)
 	params := url.Values{}
 	params.Add("query", address)
 	params.Add("location", latlng)
+	if page.Token != "" {
+		params.Add("pagetoken", page.Token)
+	}
+	if language != "" {
+		params.Add("language", language)
+	}
 	params.Add("key", g.ApiKey)
 
 	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/textsearch/json?%s", params.Encode())
 	resp, err := http.Get(uri)
 	if err != nil {
-		return status.FAILED, nil, err
+		return status.FAILED, nil, "", err
 	}
 
 	defer resp.Body.Close()
 	bytes, errRead := ioutil.ReadAll(resp.Body)
 	if errRead != nil {
-		return status.FAILED, nil, err
+		return status.FAILED, nil, "", err
 	}
 
 	var results Results
 	errUnmarshal := json.Unmarshal(bytes, &results)
 	if errUnmarshal != nil {
-		return results.Status, nil, err
+		return results.Status, nil, "", err
 	}
-	if len(results.Results) == 0 {
+	if len(results.Results) <= page.Offset {
 		if results.ErrorMessage != "" {
-			return results.Status, nil, errors.New(results.ErrorMessage)
+			return results.Status, nil, "", errors.New(results.ErrorMessage)
 		}
-		return results.Status, nil, errors.New("No results for " + address)
+		// an offset past the end of an OK page has nothing left to return
+		statusMaps := results.Status
+		if statusMaps == status.OK {
+			statusMaps = status.ZERO_RESULTS
+		}
+		return statusMaps, nil, "", errors.New("No results for " + address)
 	} else {
+		items := results.Results[page.Offset:]
+		next := ""
+		if limit > 0 && len(items) > limit {
+			items = items[:limit]
+			next = pagination.Encode(&pagination.Cursor{
+				Provider: page.Provider,
+				Query:    page.Query,
+				Token:    page.Token,
+				Offset:   page.Offset + limit,
+			})
+		} else if results.NextPageToken != "" {
+			next = pagination.Encode(&pagination.Cursor{
+				Provider: page.Provider,
+				Query:    page.Query,
+				Token:    results.NextPageToken,
+			})
+		}
+
 		list := []*entity.Address{}
-		for _, v := range results.Results {
+		for _, v := range items {
 			locationTmp := &entity.Location{
 				Lat: v.ResultItem.Location.Lat,
 				Lng: v.ResultItem.Location.Lng,
 			}
 			placeTmp := &entity.Address{
+				PlaceID:  v.PlaceID,
 				Name:     strings.Split(v.Address, ",")[0],
 				Address:  v.Address,
 				Location: locationTmp,
 			}
 			list = append(list, placeTmp)
 		}
-		return status.OK, list, nil
+		return status.OK, list, next, nil
+	}
+
+}
+
+// trafficParams adds the departure time and traffic model, Google only
+// computes traffic durations for driving from a departure time
+func trafficParams(params url.Values, options *entity.RouteOptions) error {
+	if options == nil {
+		return nil
+	}
+	if options.ArrivalTime != nil {
+		return errors.New("arrival_time is not supported for driving by google maps")
+	}
+	if options.DepartureTime != nil {
+		departure := options.DepartureTime.Unix()
+		if now := time.Now().Unix(); departure < now {
+			departure = now
+		}
+		params.Add("departure_time", strconv.FormatInt(departure, 10))
+		if options.TrafficModel != "" {
+			params.Add("traffic_model", options.TrafficModel)
+		}
+	}
+	return nil
+}
+
+var travelModes = map[string]string{
+	entity.VEHICLE_CAR:        "driving",
+	entity.VEHICLE_BICYCLE:    "bicycling",
+	entity.VEHICLE_PEDESTRIAN: "walking",
+}
+
+// modeParams sets the travel mode, Google has no truck routing so profiles with
+// dimensions, weights or hazardous goods are rejected instead of routing a car
+func modeParams(params url.Values, options *entity.RouteOptions) error {
+	if options == nil || options.Vehicle == nil {
+		params.Add("mode", "driving")
+		return nil
 	}
 
+	mode, ok := travelModes[options.Vehicle.Mode]
+	if !ok || options.Vehicle.Restricted() {
+		return fmt.Errorf("vehicle %v is not supported by google maps", options.Vehicle.Name)
+	}
+	params.Add("mode", mode)
+	return nil
+}
+
+// avoidParams adds the avoided features, Google has no unpaved roads flag nor avoid areas
+func avoidParams(params url.Values, options *entity.RouteOptions) error {
+	if options == nil {
+		return nil
+	}
+	if options.Avoids(entity.AVOID_UNPAVED) {
+		return errors.New("avoiding unpaved roads is not supported by google maps")
+	}
+	if len(options.AvoidAreas) > 0 {
+		return errors.New("avoid areas are not supported by google maps")
+	}
+
+	if len(options.Avoid) > 0 {
+		params.Add("avoid", strings.Join(options.Avoid, "|"))
+	}
+	return nil
 }
 
-func (g *GoogleMaps) Distance(origin *entity.Location, destination *entity.Location) (string, *entity.Summary, error) {
+func (g *GoogleMaps) Distance(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (string, *entity.Summary, error) {
 	from := fmt.Sprintf("%f,%f", origin.Lat, origin.Lng)
 	to := fmt.Sprintf("%f,%f", destination.Lat, destination.Lng)
 	params := url.Values{}
 	params.Add("origins", from)
 	params.Add("destinations", to)
-	params.Add("mode", "driving")
+	if err := modeParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if err := trafficParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if err := avoidParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if language != "" {
+		params.Add("language", language)
+	}
 	params.Add("key", g.ApiKey)
 
 	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/distancematrix/json?%s", params.Encode())
@@ -246,6 +471,9 @@ func (g *GoogleMaps) Distance(origin *entity.Location, destination *entity.Locat
 		Duration: response.Rows[0].Elements[0].Duration.Value,
 		Distance: response.Rows[0].Elements[0].Distance.Value,
 	}
+	if traffic := response.Rows[0].Elements[0].DurationInTraffic; traffic != nil {
+		summary.TrafficDuration = traffic.Value
+	}
 
 	if response.Rows[0].Elements[0].StatusDistance != "" && response.Rows[0].Elements[0].StatusDistance == status.ZERO_RESULTS {
 		return status.ZERO_RESULTS, nil, errors.New("failed to calculate distance")
@@ -255,13 +483,27 @@ func (g *GoogleMaps) Distance(origin *entity.Location, destination *entity.Locat
 }
 
 // TODO: ROUTES
-func (g *GoogleMaps) Route(origin *entity.Location, destination *entity.Location) (string, *entity.Route, error) {
+func (g *GoogleMaps) Route(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (string, *entity.Route, error) {
 	from := fmt.Sprintf("%f,%f", origin.Lat, origin.Lng)
 	to := fmt.Sprintf("%f,%f", destination.Lat, destination.Lng)
 	params := url.Values{}
 	params.Add("origin", from)
 	params.Add("destination", to)
-	params.Add("mode", "driving")
+	if err := modeParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if err := trafficParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if err := avoidParams(params, options); err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+	if options != nil && options.Alternatives > 0 {
+		params.Add("alternatives", "true")
+	}
+	if language != "" {
+		params.Add("language", language)
+	}
 	params.Add("key", g.ApiKey)
 
 	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/directions/json?%s", params.Encode())
@@ -285,11 +527,37 @@ func (g *GoogleMaps) Route(origin *entity.Location, destination *entity.Location
 	if len(responseRoute.Routes) <= 0 {
 		return status.ZERO_RESULTS, nil, errors.New("Route for origin or destination invalid")
 	}
-	buf := []byte(responseRoute.Routes[0].OverviewPolyline.Points)
-	coords, _, err := polyline.DecodeCoords(buf)
+
+	route, err := toRoute(responseRoute.Routes[0])
 	if err != nil {
 		return status.FAILED, nil, err
 	}
+	if options.WantsSteps() {
+		route.Steps = toSteps(responseRoute.Routes[0].Legs)
+	}
+	for i, v := range responseRoute.Routes[1:] {
+		if options == nil || i >= options.Alternatives {
+			break
+		}
+		alternative, err := toRoute(v)
+		if err != nil {
+			return status.FAILED, nil, err
+		}
+		if options.WantsSteps() {
+			alternative.Steps = toSteps(v.Legs)
+		}
+		route.Alternatives = append(route.Alternatives, alternative)
+	}
+
+	return status.OK, route, nil
+}
+
+func toRoute(r Route) (*entity.Route, error) {
+	buf := []byte(r.OverviewPolyline.Points)
+	coords, _, err := polyline.DecodeCoords(buf)
+	if err != nil {
+		return nil, err
+	}
 
 	var list []*entity.Location
 
@@ -299,14 +567,380 @@ func (g *GoogleMaps) Route(origin *entity.Location, destination *entity.Location
 	}
 
 	summaryTmp := entity.Summary{
-		Duration: responseRoute.Routes[0].Legs[0].Duration.Value,
-		Distance: responseRoute.Routes[0].Legs[0].Distance.Value,
+		Duration: r.Legs[0].Duration.Value,
+		Distance: r.Legs[0].Distance.Value,
+	}
+	if traffic := r.Legs[0].DurationInTraffic; traffic != nil {
+		summaryTmp.TrafficDuration = traffic.Value
 	}
 
-	var route = &entity.Route{
-		Summary:  summaryTmp,
+	return &entity.Route{
+		Summary: summaryTmp,
+		Legs: []*entity.Leg{
+			{Summary: summaryTmp, Start: 0, End: len(list) - 1},
+		},
 		Polyline: list,
+	}, nil
+}
+
+type ResponseDetails struct {
+	Result       PlaceResult `json:"result"`
+	Status       string      `json:"status"`
+	ErrorMessage string      `json:"error_message"`
+}
+
+type PlaceResult struct {
+	PlaceID                  string                     `json:"place_id"`
+	Name                     string                     `json:"name"`
+	Address                  string                     `json:"formatted_address"`
+	Geometry                 Geometry                   `json:"geometry"`
+	AddressComponents        []*entity.AddressComponent `json:"address_components"`
+	InternationalPhoneNumber string                     `json:"international_phone_number"`
+	Website                  string                     `json:"website"`
+	OpeningHours             *OpeningHours              `json:"opening_hours"`
+	Types                    []string                   `json:"types"`
+}
+
+type OpeningHours struct {
+	OpenNow     *bool    `json:"open_now"`
+	WeekdayText []string `json:"weekday_text"`
+}
+
+// Place closes the autocomplete session when sessionToken is the one used for the suggestions
+func (g *GoogleMaps) Place(id string, sessionToken string, language string) (string, *entity.PlaceDetails, error) {
+	params := url.Values{}
+	params.Add("place_id", id)
+	if sessionToken != "" {
+		params.Add("sessiontoken", sessionToken)
+	}
+	params.Add("fields", "place_id,name,formatted_address,geometry,address_components,international_phone_number,website,opening_hours,types")
+	if language != "" {
+		params.Add("language", language)
 	}
+	params.Add("key", g.ApiKey)
 
-	return status.OK, route, nil
+	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/details/json?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response ResponseDetails
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if response.Status != status.OK {
+		if response.ErrorMessage != "" {
+			return response.Status, nil, errors.New(response.ErrorMessage)
+		}
+		return response.Status, nil, errors.New("No results for " + id)
+	}
+
+	result := response.Result
+	details := &entity.PlaceDetails{
+		PlaceID:    result.PlaceID,
+		Name:       result.Name,
+		Address:    result.Address,
+		Location:   &result.Geometry.Location,
+		Components: result.AddressComponents,
+		Phone:      result.InternationalPhoneNumber,
+		Website:    result.Website,
+		Categories: result.Types,
+	}
+	if result.OpeningHours != nil {
+		details.OpeningHours = result.OpeningHours.WeekdayText
+		details.OpenNow = result.OpeningHours.OpenNow
+	}
+	if result.Geometry.Viewport != nil {
+		details.Viewport = &entity.Viewport{
+			Northeast: &result.Geometry.Viewport.Northeast,
+			Southwest: &result.Geometry.Viewport.Southwest,
+		}
+	}
+
+	return status.OK, details, nil
+}
+
+type ResponseAutocomplete struct {
+	Predictions  []Prediction `json:"predictions"`
+	Status       string       `json:"status"`
+	ErrorMessage string       `json:"error_message"`
+}
+
+type Prediction struct {
+	PlaceID              string               `json:"place_id"`
+	Description          string               `json:"description"`
+	StructuredFormatting StructuredFormatting `json:"structured_formatting"`
+}
+
+type StructuredFormatting struct {
+	MainText                  string             `json:"main_text"`
+	MainTextMatchedSubstrings []MatchedSubstring `json:"main_text_matched_substrings"`
+	SecondaryText             string             `json:"secondary_text"`
+}
+
+type MatchedSubstring struct {
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+}
+
+// autocompleteBiasRadius is the radius in meters around the location that
+// ranks first, Google ignores a location bias without one
+const autocompleteBiasRadius = 20000
+
+func (g *GoogleMaps) Autocomplete(input string, location *entity.Location, sessionToken string, language string) (string, []*entity.Suggestion, error) {
+	params := url.Values{}
+	params.Add("input", input)
+	if location != nil {
+		params.Add("locationbias", fmt.Sprintf("circle:%d@%f,%f", autocompleteBiasRadius, location.Lat, location.Lng))
+	}
+	if sessionToken != "" {
+		params.Add("sessiontoken", sessionToken)
+	}
+	if language != "" {
+		params.Add("language", language)
+	}
+	params.Add("key", g.ApiKey)
+
+	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/autocomplete/json?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response ResponseAutocomplete
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if response.Status != status.OK && response.Status != status.ZERO_RESULTS {
+		if response.ErrorMessage != "" {
+			return response.Status, nil, errors.New(response.ErrorMessage)
+		}
+		return response.Status, nil, errors.New("No results for " + input)
+	}
+
+	list := []*entity.Suggestion{}
+	for _, v := range response.Predictions {
+		suggestion := &entity.Suggestion{
+			PlaceID:     v.PlaceID,
+			Title:       v.StructuredFormatting.MainText,
+			Description: v.StructuredFormatting.SecondaryText,
+			Highlights:  []*entity.Highlight{},
+		}
+		if suggestion.Title == "" {
+			suggestion.Title = v.Description
+		}
+		for _, m := range v.StructuredFormatting.MainTextMatchedSubstrings {
+			suggestion.Highlights = append(suggestion.Highlights, &entity.Highlight{Offset: m.Offset, Length: m.Length})
+		}
+		list = append(list, suggestion)
+	}
+	return status.OK, list, nil
+}
+
+var nearbyTypes = map[string]string{
+	entity.CATEGORY_PHARMACY:    "pharmacy",
+	entity.CATEGORY_SUPERMARKET: "supermarket",
+	entity.CATEGORY_RESTAURANT:  "restaurant",
+	entity.CATEGORY_FUEL:        "gas_station",
+}
+
+type ResponseNearby struct {
+	Results      []NearbyResult `json:"results"`
+	Status       string         `json:"status"`
+	ErrorMessage string         `json:"error_message"`
+}
+
+type NearbyResult struct {
+	PlaceID  string   `json:"place_id"`
+	Name     string   `json:"name"`
+	Vicinity string   `json:"vicinity"`
+	Geometry Geometry `json:"geometry"`
+}
+
+// Nearby makes one request per category, Nearby Search only filters by a single type
+func (g *GoogleMaps) Nearby(location *entity.Location, radius float64, categories []string, language string) (string, []*entity.POI, error) {
+	list := []*entity.POI{}
+	seen := map[string]bool{}
+
+	for _, category := range categories {
+		kind, ok := nearbyTypes[category]
+		if !ok {
+			return status.INVALID_DATA, nil, errors.New("invalid category " + category)
+		}
+
+		params := url.Values{}
+		params.Add("location", fmt.Sprintf("%f,%f", location.Lat, location.Lng))
+		params.Add("radius", fmt.Sprintf("%.0f", radius))
+		params.Add("type", kind)
+		if language != "" {
+			params.Add("language", language)
+		}
+		params.Add("key", g.ApiKey)
+
+		var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/place/nearbysearch/json?%s", params.Encode())
+		resp, err := http.Get(uri)
+		if err != nil {
+			return status.FAILED, nil, err
+		}
+
+		bytes, errRead := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if errRead != nil {
+			return status.FAILED, nil, errRead
+		}
+
+		var response ResponseNearby
+		errUnmarshal := json.Unmarshal(bytes, &response)
+		if errUnmarshal != nil {
+			return status.FAILED, nil, errUnmarshal
+		}
+
+		if response.Status != status.OK && response.Status != status.ZERO_RESULTS {
+			if response.ErrorMessage != "" {
+				return response.Status, nil, errors.New(response.ErrorMessage)
+			}
+			return response.Status, nil, errors.New("Nearby search failed for " + category)
+		}
+
+		for _, v := range response.Results {
+			if seen[v.PlaceID] {
+				continue
+			}
+			seen[v.PlaceID] = true
+			locationTmp := v.Geometry.Location
+			list = append(list, &entity.POI{
+				PlaceID:  v.PlaceID,
+				Name:     v.Name,
+				Address:  v.Vicinity,
+				Location: &locationTmp,
+				Category: category,
+			})
+		}
+	}
+
+	return status.OK, list, nil
+}
+
+type ResponseElevation struct {
+	Results      []ElevationResult `json:"results"`
+	Status       string            `json:"status"`
+	ErrorMessage string            `json:"error_message"`
+}
+
+type ElevationResult struct {
+	Elevation float64 `json:"elevation"`
+}
+
+// elevationBatch keeps every request well under the 512 locations and URL length limits
+const elevationBatch = 256
+
+// Elevation sends the points as an encoded polyline in batches
+func (g *GoogleMaps) Elevation(points []*entity.Location) (string, []float64, error) {
+	list := []float64{}
+	for start := 0; start < len(points); start += elevationBatch {
+		end := start + elevationBatch
+		if end > len(points) {
+			end = len(points)
+		}
+
+		params := url.Values{}
+		params.Add("locations", "enc:"+geometry.EncodePolyline(points[start:end]))
+		params.Add("key", g.ApiKey)
+
+		var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/elevation/json?%s", params.Encode())
+		resp, err := http.Get(uri)
+		if err != nil {
+			return status.FAILED, nil, err
+		}
+
+		bytes, errRead := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if errRead != nil {
+			return status.FAILED, nil, errRead
+		}
+
+		var response ResponseElevation
+		errUnmarshal := json.Unmarshal(bytes, &response)
+		if errUnmarshal != nil {
+			return status.FAILED, nil, errUnmarshal
+		}
+		if len(response.Results) != end-start {
+			if response.ErrorMessage != "" {
+				return response.Status, nil, errors.New(response.ErrorMessage)
+			}
+			return status.ZERO_RESULTS, nil, errors.New("no elevation for the points")
+		}
+
+		for _, v := range response.Results {
+			list = append(list, v.Elevation)
+		}
+	}
+	return status.OK, list, nil
+}
+
+type ResponseTimeZone struct {
+	TimeZoneID   string  `json:"timeZoneId"`
+	RawOffset    float64 `json:"rawOffset"`
+	DstOffset    float64 `json:"dstOffset"`
+	Status       string  `json:"status"`
+	ErrorMessage string  `json:"errorMessage"`
+}
+
+func (g *GoogleMaps) TimeZone(location *entity.Location, at time.Time) (string, *entity.TimeZone, error) {
+	params := url.Values{}
+	params.Add("location", fmt.Sprintf("%f,%f", location.Lat, location.Lng))
+	params.Add("timestamp", strconv.FormatInt(at.Unix(), 10))
+	params.Add("key", g.ApiKey)
+
+	var uri string = fmt.Sprintf("https://maps.googleapis.com/maps/api/timezone/json?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response ResponseTimeZone
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+	if response.TimeZoneID == "" {
+		if response.ErrorMessage != "" {
+			return response.Status, nil, errors.New(response.ErrorMessage)
+		}
+		return status.ZERO_RESULTS, nil, errors.New("no timezone for the location")
+	}
+
+	if zone, err := timezone.At(response.TimeZoneID, at); err == nil {
+		return status.OK, zone, nil
+	}
+	offset := int(response.RawOffset + response.DstOffset)
+	return status.OK, &entity.TimeZone{
+		ID:        response.TimeZoneID,
+		Offset:    offset,
+		UTCOffset: time.Unix(0, 0).In(time.FixedZone("", offset)).Format("-07:00"),
+		DST:       response.DstOffset != 0,
+	}, nil
 }
@@ -5,11 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/heremaps/flexible-polyline/golang/flexpolyline"
+	"maps.patio.com/addrparse"
 	"maps.patio.com/entity"
+	"maps.patio.com/pagination"
 	status "maps.patio.com/responses"
 )
 
@@ -23,8 +29,9 @@ type Items struct {
 }
 
 type Item struct {
+	ID       string          `json:"id"`
 	Title    string          `json:"title"`
-	Address  AddressLabel    `json:"address"`
+	Address  LookupAddress   `json:"address"`
 	Location entity.Location `json:"position"`
 }
 
@@ -41,13 +48,163 @@ type Route struct {
 }
 
 type Section struct {
-	Summary  Summary `json:"summary"`
-	Polyline string  `json:"polyline"`
+	Summary  Summary  `json:"summary"`
+	Polyline string   `json:"polyline"`
+	Actions  []Action `json:"actions"`
+}
+
+type Action struct {
+	Action      string  `json:"action"`
+	Direction   string  `json:"direction"`
+	Severity    string  `json:"severity"`
+	Instruction string  `json:"instruction"`
+	Duration    float64 `json:"duration"`
+	Length      float64 `json:"length"`
+	Offset      int     `json:"offset"`
+}
+
+// maneuver translates the HERE action to the Google maneuver names
+func maneuver(a Action) string {
+	if a.Direction == "" || a.Direction == "middle" {
+		switch a.Action {
+		case "ferry":
+			return "ferry"
+		case "continue", "keep":
+			return "straight"
+		}
+		return ""
+	}
+
+	switch a.Action {
+	case "turn":
+		switch a.Severity {
+		case "light":
+			return "turn-slight-" + a.Direction
+		case "heavy":
+			return "turn-sharp-" + a.Direction
+		}
+		return "turn-" + a.Direction
+	case "keep":
+		return "keep-" + a.Direction
+	case "uTurn":
+		return "uturn-" + a.Direction
+	case "ramp", "exit":
+		return "ramp-" + a.Direction
+	case "roundaboutEnter", "roundaboutPass", "roundaboutExit":
+		return "roundabout-" + a.Direction
+	}
+	return ""
 }
 
 type Summary struct {
-	Duration float64 `json:"duration"`
-	Distance float64 `json:"length"`
+	Duration     float64 `json:"duration"`
+	BaseDuration float64 `json:"baseDuration"`
+	Distance     float64 `json:"length"`
+}
+
+// toEntity keeps the duration as before unless traffic was requested, then
+// baseDuration is the free-flow time and duration the one with traffic
+func (s Summary) toEntity(traffic bool) entity.Summary {
+	summary := entity.Summary{Duration: s.Duration, Distance: s.Distance}
+	if traffic && s.BaseDuration > 0 {
+		summary.Duration = s.BaseDuration
+		summary.TrafficDuration = s.Duration
+	}
+	return summary
+}
+
+var avoidFeatures = map[string]string{
+	entity.AVOID_TOLLS:    "tollRoad",
+	entity.AVOID_HIGHWAYS: "controlledAccessHighway",
+	entity.AVOID_FERRIES:  "ferry",
+	entity.AVOID_UNPAVED:  "dirtRoad",
+}
+
+// avoidParams adds the avoided features and areas, areas are "bbox:west,south,east,north"
+// or "polygon:lat,lng;lat,lng;..." joined by "|"
+func avoidParams(params url.Values, options *entity.RouteOptions) {
+	if options == nil {
+		return
+	}
+
+	features := []string{}
+	for _, v := range options.Avoid {
+		if feature, ok := avoidFeatures[v]; ok {
+			features = append(features, feature)
+		}
+	}
+	if len(features) > 0 {
+		params.Add("avoid[features]", strings.Join(features, ","))
+	}
+
+	areas := []string{}
+	for _, area := range options.AvoidAreas {
+		if area.BoundingBox != nil {
+			areas = append(areas, fmt.Sprintf("bbox:%f,%f,%f,%f",
+				area.BoundingBox.Southwest.Lng, area.BoundingBox.Southwest.Lat,
+				area.BoundingBox.Northeast.Lng, area.BoundingBox.Northeast.Lat))
+		} else if len(area.Polygon) > 0 {
+			points := []string{}
+			for _, v := range area.Polygon {
+				points = append(points, fmt.Sprintf("%f,%f", v.Lat, v.Lng))
+			}
+			areas = append(areas, "polygon:"+strings.Join(points, ";"))
+		}
+	}
+	if len(areas) > 0 {
+		params.Add("avoid[areas]", strings.Join(areas, "|"))
+	}
+}
+
+// vehicleParams sets the transport mode, bicycle unless a vehicle profile is
+// requested, and the truck restrictions in centimeters and kilograms
+func vehicleParams(params url.Values, options *entity.RouteOptions) {
+	if options == nil || options.Vehicle == nil {
+		params.Add("transportMode", "bicycle")
+		return
+	}
+
+	vehicle := options.Vehicle
+	params.Add("transportMode", vehicle.Mode)
+	if vehicle.Mode != entity.VEHICLE_TRUCK {
+		return
+	}
+	centimeters := func(key string, meters float64) {
+		if meters > 0 {
+			params.Add(key, strconv.Itoa(int(math.Ceil(meters*100))))
+		}
+	}
+	centimeters("vehicle[height]", vehicle.Height)
+	centimeters("vehicle[width]", vehicle.Width)
+	centimeters("vehicle[length]", vehicle.Length)
+	if vehicle.GrossWeight > 0 {
+		params.Add("vehicle[grossWeight]", strconv.Itoa(int(math.Ceil(vehicle.GrossWeight))))
+	}
+	if vehicle.WeightPerAxle > 0 {
+		params.Add("vehicle[weightPerAxle]", strconv.Itoa(int(math.Ceil(vehicle.WeightPerAxle))))
+	}
+	if vehicle.AxleCount > 0 {
+		params.Add("vehicle[axleCount]", strconv.Itoa(vehicle.AxleCount))
+	}
+	if vehicle.TrailerCount > 0 {
+		params.Add("vehicle[trailerCount]", strconv.Itoa(vehicle.TrailerCount))
+	}
+	if len(vehicle.HazardousGoods) > 0 {
+		params.Add("vehicle[shippedHazardousGoods]", strings.Join(vehicle.HazardousGoods, ","))
+	}
+}
+
+// timeParams adds the departure or arrival time HERE uses for traffic
+func timeParams(params url.Values, options *entity.RouteOptions) {
+	if options == nil {
+		return
+	}
+	if options.DepartureTime != nil {
+		params.Add("departureTime", options.DepartureTime.Format(time.RFC3339))
+	}
+	if options.ArrivalTime != nil {
+		params.Add("arrivalTime", options.ArrivalTime.Format(time.RFC3339))
+	}
 }
 
 func New(key string) *HereMaps {
@@ -59,10 +216,34 @@ func (h *HereMaps) Provider() string {
 	return "HERE MAPS"
 }
 
-func (h *HereMaps) Geocoding(address string) (string, *entity.Address, error) {
+func (h *HereMaps) Geocoding(address string, language string) (string, *entity.Address, error) {
 	params := url.Values{}
 	params.Add("q", address)
+
+	return h.geocode(params, address, language)
+}
+
+// GeocodingStructured sends the street part as free text and the city as a qualified field
+func (h *HereMaps) GeocodingStructured(parsed *entity.ParsedAddress, language string) (string, *entity.Address, error) {
+	address := addrparse.Street(parsed)
+
+	params := url.Values{}
+	if address != "" {
+		params.Add("q", address)
+	}
+	if parsed.City != "" {
+		params.Add("qq", "city="+parsed.City)
+		params.Add("in", "countryCode:BOL")
+	}
+
+	return h.geocode(params, addrparse.Canonical(parsed), language)
+}
+
+func (h *HereMaps) geocode(params url.Values, address string, language string) (string, *entity.Address, error) {
 	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
 
 	var uri string = fmt.Sprintf("https://geocode.search.hereapi.com/v1/geocode?%s", params.Encode())
 
@@ -89,6 +270,7 @@ func (h *HereMaps) Geocoding(address string) (string, *entity.Address, error) {
 		return status.ZERO_RESULTS, nil, errors.New("No results for " + address)
 	} else {
 		newAddress := &entity.Address{
+			PlaceID:  items.Items[0].ID,
 			Name:     items.Items[0].Title,
 			Address:  items.Items[0].Address.Label,
 			Location: &items.Items[0].Location,
@@ -97,12 +279,14 @@ func (h *HereMaps) Geocoding(address string) (string, *entity.Address, error) {
 	}
 }
 
-func (h *HereMaps) ReverseGeocoding(location *entity.Location) (string, *entity.Address, error) {
+func (h *HereMaps) ReverseGeocoding(location *entity.Location, language string) (string, *entity.Address, error) {
 	latlng := fmt.Sprintf("%f,%f", location.Lat, location.Lng)
 	params := url.Values{}
 	params.Add("at", latlng)
 	params.Add("apikey", h.ApiKey)
-	params.Add("lang", "en-US")
+	if language != "" {
+		params.Add("lang", language)
+	}
 
 	var uri string = fmt.Sprintf("https://revgeocode.search.hereapi.com/v1/revgeocode?%s", params.Encode())
 
@@ -130,74 +314,109 @@ func (h *HereMaps) ReverseGeocoding(location *entity.Location) (string, *entity.
 		return status.ZERO_RESULTS, nil, errors.New("No results for " + latlng)
 	} else {
 		address := &entity.Address{
-			Name:     items.Items[0].Title,
-			Address:  items.Items[0].Address.Label,
-			Location: &items.Items[0].Location,
+			PlaceID:        items.Items[0].ID,
+			Name:           items.Items[0].Title,
+			Address:        items.Items[0].Address.Label,
+			Location:       &items.Items[0].Location,
+			Administrative: items.Items[0].Address.administrative(),
 		}
 		return status.OK, address, nil
 	}
 }
 
-func (h *HereMaps) Search(address string, location *entity.Location) (string, []*entity.Address, error) {
+// Search has no page tokens on HERE, so pages are emulated by asking for
+// offset+limit suggestions and skipping the ones already returned
+func (h *HereMaps) Search(address string, location *entity.Location, limit int, cursor string, language string) (string, []*entity.Address, string, error) {
+	page, err := pagination.Decode(cursor, h.Provider(), address)
+	if err != nil {
+		return status.INVALID_DATA, nil, "", err
+	}
+
+	size := 20
+	if limit > 0 {
+		size = page.Offset + limit
+	}
+	if size > 100 {
+		size = 100
+	}
 
 	latlng := fmt.Sprintf("%f,%f", location.Lat, location.Lng)
 	params := url.Values{}
 	params.Add("at", latlng)
 	params.Add("q", address)
+	params.Add("limit", strconv.Itoa(size))
 	params.Add("apikey", h.ApiKey)
-	params.Add("lang", "en-US")
+	if language != "" {
+		params.Add("lang", language)
+	}
 
 	var uri string = fmt.Sprintf("https://autosuggest.search.hereapi.com/v1/autosuggest?%s", params.Encode())
 	resp, err := http.Get(uri)
 	if err != nil {
-		return status.FAILED, nil, err
+		return status.FAILED, nil, "", err
 	}
 
 	defer resp.Body.Close()
 	bytes, errRead := ioutil.ReadAll(resp.Body)
 	if errRead != nil {
-		return status.FAILED, nil, err
+		return status.FAILED, nil, "", err
 	}
 
 	var items Items
 	errUnmarshal := json.Unmarshal(bytes, &items)
 	if errUnmarshal != nil {
-		return status.FAILED, nil, err
+		return status.FAILED, nil, "", err
 	}
 
-	if len(items.Items) == 0 {
+	if len(items.Items) <= page.Offset {
 		if items.ErrorDescription != "" {
-			return status.ZERO_RESULTS, nil, errors.New(items.ErrorDescription)
+			return status.ZERO_RESULTS, nil, "", errors.New(items.ErrorDescription)
 		}
-		return status.ZERO_RESULTS, nil, errors.New("No results for " + address)
+		return status.ZERO_RESULTS, nil, "", errors.New("No results for " + address)
 	} else {
+		results := items.Items[page.Offset:]
+		next := ""
+		if limit > 0 && len(items.Items) == size && size < 100 {
+			next = pagination.Encode(&pagination.Cursor{
+				Provider: page.Provider,
+				Query:    page.Query,
+				Offset:   page.Offset + len(results),
+			})
+		}
+
 		list := []*entity.Address{}
-		for _, v := range items.Items {
+		for _, v := range results {
 			locationTmp := &entity.Location{
 				Lat: v.Location.Lat,
 				Lng: v.Location.Lng,
 			}
 			placeTmp := &entity.Address{
+				PlaceID:  v.ID,
 				Name:     v.Title,
 				Address:  v.Address.Label,
 				Location: locationTmp,
 			}
 			list = append(list, placeTmp)
 		}
-		return status.OK, list, nil
+		return status.OK, list, next, nil
 	}
 
 }
 
-func (h *HereMaps) Distance(origin *entity.Location, destination *entity.Location) (string, *entity.Summary, error) {
+func (h *HereMaps) Distance(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (string, *entity.Summary, error) {
 	from := fmt.Sprintf("%f,%f", origin.Lat, origin.Lng)
 	to := fmt.Sprintf("%f,%f", destination.Lat, destination.Lng)
 	params := url.Values{}
 	params.Add("origin", from)
 	params.Add("destination", to)
-	params.Add("transportMode", "bicycle")
+	vehicleParams(params, options)
 	params.Add("return", "summary")
+	timeParams(params, options)
+	avoidParams(params, options)
 	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
 
 	var uri string = fmt.Sprintf("https://router.hereapi.com/v8/routes?%s", params.Encode())
 	resp, err := http.Get(uri)
@@ -224,22 +443,31 @@ func (h *HereMaps) Distance(origin *entity.Location, destination *entity.Locatio
 		return status.ZERO_RESULTS, nil, errors.New("Distance for origin or destination invalid")
 	}
 
-	var summary = &entity.Summary{
-		Duration: response.Routes[0].Sections[0].Summary.Duration,
-		Distance: response.Routes[0].Sections[0].Summary.Distance,
-	}
+	summary := response.Routes[0].Sections[0].Summary.toEntity(options.Traffic())
 
-	return status.OK, summary, nil
+	return status.OK, &summary, nil
 }
-func (h *HereMaps) Route(origin *entity.Location, destination *entity.Location) (string, *entity.Route, error) {
+func (h *HereMaps) Route(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (string, *entity.Route, error) {
 	from := fmt.Sprintf("%f,%f", origin.Lat, origin.Lng)
 	to := fmt.Sprintf("%f,%f", destination.Lat, destination.Lng)
 	params := url.Values{}
 	params.Add("origin", from)
 	params.Add("destination", to)
-	params.Add("transportMode", "bicycle")
-	params.Add("return", "polyline,summary")
+	vehicleParams(params, options)
+	if options.WantsSteps() {
+		params.Add("return", "polyline,summary,actions,instructions")
+	} else {
+		params.Add("return", "polyline,summary")
+	}
+	timeParams(params, options)
+	avoidParams(params, options)
+	if options != nil && options.Alternatives > 0 {
+		params.Add("alternatives", strconv.Itoa(options.Alternatives))
+	}
 	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
 
 	var uri string = fmt.Sprintf("https://router.hereapi.com/v8/routes?%s", params.Encode())
 	resp, err := http.Get(uri)
@@ -266,27 +494,522 @@ func (h *HereMaps) Route(origin *entity.Location, destination *entity.Location)
 		return status.ZERO_RESULTS, nil, errors.New("Route for origin or destination invalid")
 	}
 
-	poly, err := flexpolyline.Decode(response.Routes[0].Sections[0].Polyline)
+	route, err := toRoute(response.Routes[0], options.Traffic(), options.WantsSteps())
 	if err != nil {
 		return status.FAILED, nil, err
 	}
+	for i, v := range response.Routes[1:] {
+		if options == nil || i >= options.Alternatives {
+			break
+		}
+		alternative, err := toRoute(v, options.Traffic(), options.WantsSteps())
+		if err != nil {
+			return status.FAILED, nil, err
+		}
+		route.Alternatives = append(route.Alternatives, alternative)
+	}
 
+	return status.OK, route, nil
+}
+
+// toRoute joins the sections polylines and keeps one leg per section, the
+// action offsets are indexes into the polyline of their section
+func toRoute(r Route, traffic bool, steps bool) (*entity.Route, error) {
 	var list []*entity.Location
+	var legs []*entity.Leg
+	var stepList []*entity.Step
+	summaryTmp := entity.Summary{}
+
+	for _, section := range r.Sections {
+		poly, err := flexpolyline.Decode(section.Polyline)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, v := range poly.Coordinates() {
-		var locationTmp = entity.Location{Lat: v.Lat, Lng: v.Lng}
-		list = append(list, &locationTmp)
-	}
+		coords := poly.Coordinates()
+		if steps {
+			for _, a := range section.Actions {
+				step := &entity.Step{
+					Instruction: a.Instruction,
+					Maneuver:    maneuver(a),
+					Distance:    a.Length,
+					Duration:    a.Duration,
+				}
+				if a.Offset >= 0 && a.Offset < len(coords) {
+					step.Location = &entity.Location{Lat: coords[a.Offset].Lat, Lng: coords[a.Offset].Lng}
+				}
+				stepList = append(stepList, step)
+			}
+		}
 
-	summaryTmp := entity.Summary{
-		Duration: response.Routes[0].Sections[0].Summary.Duration,
-		Distance: response.Routes[0].Sections[0].Summary.Distance,
+		// Consecutive sections usually share their boundary point, the leg
+		// then starts on the last point of the previous one
+		start := len(list)
+		if len(list) > 0 && len(coords) > 0 && list[len(list)-1].Lat == coords[0].Lat && list[len(list)-1].Lng == coords[0].Lng {
+			coords = coords[1:]
+			start = len(list) - 1
+		}
+		for _, v := range coords {
+			var locationTmp = entity.Location{Lat: v.Lat, Lng: v.Lng}
+			list = append(list, &locationTmp)
+		}
+
+		sectionSummary := section.Summary.toEntity(traffic)
+		legs = append(legs, &entity.Leg{
+			Summary: sectionSummary,
+			Start:   start,
+			End:     len(list) - 1,
+		})
+		summaryTmp.Duration += sectionSummary.Duration
+		summaryTmp.TrafficDuration += sectionSummary.TrafficDuration
+		summaryTmp.Distance += sectionSummary.Distance
 	}
 
-	var route = &entity.Route{
+	return &entity.Route{
 		Summary:  summaryTmp,
+		Legs:     legs,
 		Polyline: list,
+		Steps:    stepList,
+	}, nil
+}
+
+// MatchResponse is the Route Matching v8 routelinks answer, checked against
+// the example of the developer guide:
+//
+//	{"response": {"route": [{"leg": [{"length": 1520, "travelTime": 190,
+//	  "link": [{"linkId": "+1181346440", "shape": [-17.7831, -63.1821, -17.7840, -63.1830]}]}]}]},
+//	 "TracePoints": [{"latMatched": -17.7831, "lonMatched": -63.1821, "confidenceValue": 1}]}
+type MatchResponse struct {
+	Response         MatchRoutes       `json:"response"`
+	TracePoints      []MatchTracePoint `json:"TracePoints"`
+	ErrorDescription string            `json:"error_description"`
+}
+
+type MatchRoutes struct {
+	Route []MatchRoute `json:"route"`
+}
+
+type MatchRoute struct {
+	Leg []MatchLeg `json:"leg"`
+}
+
+type MatchLeg struct {
+	Link       []MatchLink `json:"link"`
+	Length     float64     `json:"length"`
+	TravelTime float64     `json:"travelTime"`
+}
+
+type MatchLink struct {
+	Shape []float64 `json:"shape"`
+}
+
+type MatchTracePoint struct {
+	LatMatched      float64 `json:"latMatched"`
+	LonMatched      float64 `json:"lonMatched"`
+	ConfidenceValue float64 `json:"confidenceValue"`
+}
+
+func (h *HereMaps) Match(trace []*entity.TracePoint) (string, *entity.Match, error) {
+	csv := "LATITUDE,LONGITUDE,TIMESTAMP\n"
+	for _, v := range trace {
+		csv += fmt.Sprintf("%f,%f,%s\n", v.Lat, v.Lng, time.Unix(v.Timestamp, 0).UTC().Format(time.RFC3339))
 	}
 
-	return status.OK, route, nil
+	params := url.Values{}
+	params.Add("routemode", "car")
+	params.Add("apikey", h.ApiKey)
+
+	var uri string = fmt.Sprintf("https://routematching.hereapi.com/v8/match/routelinks?%s", params.Encode())
+	resp, err := http.Post(uri, "text/csv", strings.NewReader(csv))
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response MatchResponse
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if len(response.Response.Route) <= 0 {
+		if response.ErrorDescription != "" {
+			return status.ZERO_RESULTS, nil, errors.New(response.ErrorDescription)
+		}
+		return status.ZERO_RESULTS, nil, errors.New("No match for trace")
+	}
+
+	match := &entity.Match{
+		Polyline: []*entity.Location{},
+		Points:   []*entity.MatchedPoint{},
+	}
+	for _, leg := range response.Response.Route[0].Leg {
+		match.Distance += leg.Length
+		match.Duration += leg.TravelTime
+		for _, link := range leg.Link {
+			for i := 0; i+1 < len(link.Shape); i += 2 {
+				match.Polyline = append(match.Polyline, &entity.Location{Lat: link.Shape[i], Lng: link.Shape[i+1]})
+			}
+		}
+	}
+
+	for _, v := range response.TracePoints {
+		match.Points = append(match.Points, &entity.MatchedPoint{
+			Location:   &entity.Location{Lat: v.LatMatched, Lng: v.LonMatched},
+			Confidence: v.ConfidenceValue,
+			Matched:    true,
+		})
+	}
+
+	return status.OK, match, nil
+}
+
+type LookupItem struct {
+	ID               string          `json:"id"`
+	Title            string          `json:"title"`
+	Address          LookupAddress   `json:"address"`
+	Location         entity.Location `json:"position"`
+	MapView          *MapView        `json:"mapView"`
+	Categories       []Category      `json:"categories"`
+	Contacts         []Contact       `json:"contacts"`
+	OpeningHours     []OpeningHours  `json:"openingHours"`
+	ErrorDescription string          `json:"error_description"`
+}
+
+type LookupAddress struct {
+	Label       string `json:"label"`
+	CountryCode string `json:"countryCode"`
+	CountryName string `json:"countryName"`
+	State       string `json:"state"`
+	County      string `json:"county"`
+	City        string `json:"city"`
+	District    string `json:"district"`
+	Subdistrict string `json:"subdistrict"`
+	Street      string `json:"street"`
+	PostalCode  string `json:"postalCode"`
+	HouseNumber string `json:"houseNumber"`
+}
+
+// administrative maps the HERE address levels, state is the department,
+// county the province and city the municipality
+func (a *LookupAddress) administrative() *entity.Administrative {
+	countryCode := a.CountryCode
+	if code, ok := countryCodes[countryCode]; ok {
+		countryCode = code
+	}
+	return &entity.Administrative{
+		Country:       a.CountryName,
+		CountryCode:   countryCode,
+		Department:    a.State,
+		Province:      a.County,
+		Municipality:  a.City,
+		District:      a.District,
+		Neighbourhood: a.Subdistrict,
+		PostalCode:    a.PostalCode,
+	}
+}
+
+type MapView struct {
+	West  float64 `json:"west"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+}
+
+type Category struct {
+	Name string `json:"name"`
+}
+
+type Contact struct {
+	Phone []ContactValue `json:"phone"`
+	Www   []ContactValue `json:"www"`
+}
+
+type ContactValue struct {
+	Value string `json:"value"`
+}
+
+type OpeningHours struct {
+	Text   []string `json:"text"`
+	IsOpen *bool    `json:"isOpen"`
+}
+
+// Place ignores sessionToken, HERE bills every request on its own
+func (h *HereMaps) Place(id string, sessionToken string, language string) (string, *entity.PlaceDetails, error) {
+	params := url.Values{}
+	params.Add("id", id)
+	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
+
+	var uri string = fmt.Sprintf("https://lookup.search.hereapi.com/v1/lookup?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var item LookupItem
+	errUnmarshal := json.Unmarshal(bytes, &item)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	// Errors come back with the message in the title and no id
+	if item.ID == "" {
+		if item.ErrorDescription != "" {
+			return status.ZERO_RESULTS, nil, errors.New(item.ErrorDescription)
+		}
+		if item.Title != "" {
+			return status.ZERO_RESULTS, nil, errors.New(item.Title)
+		}
+		return status.ZERO_RESULTS, nil, errors.New("No results for " + id)
+	}
+
+	details := &entity.PlaceDetails{
+		PlaceID:    item.ID,
+		Name:       item.Title,
+		Address:    item.Address.Label,
+		Location:   &item.Location,
+		Components: lookupComponents(&item.Address),
+		Categories: []string{},
+	}
+	for _, v := range item.Categories {
+		details.Categories = append(details.Categories, v.Name)
+	}
+	for _, v := range item.Contacts {
+		if details.Phone == "" && len(v.Phone) > 0 {
+			details.Phone = v.Phone[0].Value
+		}
+		if details.Website == "" && len(v.Www) > 0 {
+			details.Website = v.Www[0].Value
+		}
+	}
+	for _, v := range item.OpeningHours {
+		details.OpeningHours = append(details.OpeningHours, v.Text...)
+		if details.OpenNow == nil {
+			details.OpenNow = v.IsOpen
+		}
+	}
+	if item.MapView != nil {
+		details.Viewport = &entity.Viewport{
+			Northeast: &entity.Location{Lat: item.MapView.North, Lng: item.MapView.East},
+			Southwest: &entity.Location{Lat: item.MapView.South, Lng: item.MapView.West},
+		}
+	}
+
+	return status.OK, details, nil
+}
+
+// lookupComponents returns the HERE address fields with Google style types
+func lookupComponents(address *LookupAddress) []*entity.AddressComponent {
+	fields := []struct {
+		long  string
+		short string
+		kind  string
+	}{
+		{address.HouseNumber, address.HouseNumber, "street_number"},
+		{address.Street, address.Street, "route"},
+		{address.District, address.District, "sublocality"},
+		{address.City, address.City, "locality"},
+		{address.County, address.County, "administrative_area_level_2"},
+		{address.State, address.State, "administrative_area_level_1"},
+		{address.CountryName, address.CountryCode, "country"},
+		{address.PostalCode, address.PostalCode, "postal_code"},
+	}
+
+	list := []*entity.AddressComponent{}
+	for _, v := range fields {
+		if v.long != "" {
+			list = append(list, &entity.AddressComponent{LongName: v.long, ShortName: v.short, Types: []string{v.kind}})
+		}
+	}
+	return list
+}
+
+type Suggestions struct {
+	Items            []SuggestionItem `json:"items"`
+	ErrorDescription string           `json:"error_description"`
+}
+
+type SuggestionItem struct {
+	ID           string           `json:"id"`
+	Title        string           `json:"title"`
+	Address      AddressLabel     `json:"address"`
+	Location     *entity.Location `json:"position"`
+	Highlighting Highlighting     `json:"highlighting"`
+}
+
+type Highlighting struct {
+	Title []Range `json:"title"`
+}
+
+type Range struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Autocomplete ignores sessionToken, HERE bills every request on its own
+func (h *HereMaps) Autocomplete(input string, location *entity.Location, sessionToken string, language string) (string, []*entity.Suggestion, error) {
+	params := url.Values{}
+	params.Add("q", input)
+	if location != nil {
+		params.Add("at", fmt.Sprintf("%f,%f", location.Lat, location.Lng))
+	} else {
+		params.Add("in", "countryCode:BOL")
+	}
+	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
+
+	var uri string = fmt.Sprintf("https://autosuggest.search.hereapi.com/v1/autosuggest?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response Suggestions
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if response.ErrorDescription != "" {
+		return status.FAILED, nil, errors.New(response.ErrorDescription)
+	}
+
+	list := []*entity.Suggestion{}
+	for _, v := range response.Items {
+		// Query suggestions have no id, they only refine the text
+		if v.ID == "" {
+			continue
+		}
+		suggestion := &entity.Suggestion{
+			PlaceID:     v.ID,
+			Title:       v.Title,
+			Description: v.Address.Label,
+			Highlights:  []*entity.Highlight{},
+			Location:    v.Location,
+		}
+		for _, r := range v.Highlighting.Title {
+			suggestion.Highlights = append(suggestion.Highlights, &entity.Highlight{Offset: r.Start, Length: r.End - r.Start})
+		}
+		list = append(list, suggestion)
+	}
+	return status.OK, list, nil
+}
+
+var browseCategories = map[string]string{
+	entity.CATEGORY_PHARMACY:    "600-6400-0070",
+	entity.CATEGORY_SUPERMARKET: "600-6300-0066",
+	entity.CATEGORY_RESTAURANT:  "100-1000",
+	entity.CATEGORY_FUEL:        "700-7600-0116",
+}
+
+type BrowseItems struct {
+	Items            []BrowseItem `json:"items"`
+	ErrorDescription string       `json:"error_description"`
+}
+
+type BrowseItem struct {
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	Address    AddressLabel     `json:"address"`
+	Location   entity.Location  `json:"position"`
+	Distance   float64          `json:"distance"`
+	Categories []BrowseCategory `json:"categories"`
+}
+
+type BrowseCategory struct {
+	ID string `json:"id"`
+}
+
+func (h *HereMaps) Nearby(location *entity.Location, radius float64, categories []string, language string) (string, []*entity.POI, error) {
+	ids := []string{}
+	for _, category := range categories {
+		id, ok := browseCategories[category]
+		if !ok {
+			return status.INVALID_DATA, nil, errors.New("invalid category " + category)
+		}
+		ids = append(ids, id)
+	}
+
+	params := url.Values{}
+	params.Add("in", fmt.Sprintf("circle:%f,%f;r=%.0f", location.Lat, location.Lng, radius))
+	params.Add("categories", strings.Join(ids, ","))
+	params.Add("limit", "100")
+	params.Add("apikey", h.ApiKey)
+	if language != "" {
+		params.Add("lang", language)
+	}
+
+	var uri string = fmt.Sprintf("https://browse.search.hereapi.com/v1/browse?%s", params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var items BrowseItems
+	errUnmarshal := json.Unmarshal(bytes, &items)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if items.ErrorDescription != "" {
+		return status.FAILED, nil, errors.New(items.ErrorDescription)
+	}
+
+	list := []*entity.POI{}
+	for _, v := range items.Items {
+		locationTmp := v.Location
+		list = append(list, &entity.POI{
+			PlaceID:  v.ID,
+			Name:     v.Title,
+			Address:  v.Address.Label,
+			Location: &locationTmp,
+			Category: browseCategory(v.Categories, categories),
+			Distance: v.Distance,
+		})
+	}
+
+	return status.OK, list, nil
+}
+
+// browseCategory returns which of the requested categories the item belongs to
+func browseCategory(itemCategories []BrowseCategory, requested []string) string {
+	for _, c := range itemCategories {
+		for _, category := range requested {
+			if strings.HasPrefix(c.ID, browseCategories[category]) {
+				return category
+			}
+		}
+	}
+	if len(requested) > 0 {
+		return requested[0]
+	}
+	return ""
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/matching"
+	"maps.patio.com/repository/osrm"
+	status "maps.patio.com/responses"
+)
+
+type Matcher interface {
+	Match(trace []*entity.TracePoint) (status string, match *entity.Match, err error)
+}
+
+// LocalMatcher matches the trace against the provider route between its first and last points
+type LocalMatcher struct {
+	Repo    Repository
+	Options matching.Options
+}
+
+func (l *LocalMatcher) Match(trace []*entity.TracePoint) (string, *entity.Match, error) {
+	if len(trace) < 2 {
+		return status.INVALID_DATA, nil, errors.New("trace must have at least two points")
+	}
+
+	origin := &entity.Location{Lat: trace[0].Lat, Lng: trace[0].Lng}
+	destination := &entity.Location{Lat: trace[len(trace)-1].Lat, Lng: trace[len(trace)-1].Lng}
+	statusMaps, route, err := l.Repo.Route(origin, destination, nil, "")
+	if err != nil {
+		return statusMaps, nil, err
+	}
+
+	match := matching.Match(route.Polyline, trace, l.Options)
+	matched := 0
+	for _, v := range match.Points {
+		if v.Matched {
+			matched++
+		}
+	}
+	if matched < 2 || float64(matched) < l.Options.MinMatched*float64(len(trace)) {
+		return status.ZERO_RESULTS, nil, fmt.Errorf("only %d of %d trace points are on the route", matched, len(trace))
+	}
+
+	return status.OK, match, nil
+}
+
+func NewMatcher(config *configuration.Configuration, repo Repository) (Matcher, error) {
+	var matcher Matcher
+	var err error
+
+	switch config.MATCH.Provider {
+	case "", "local":
+		matcher = &LocalMatcher{Repo: repo, Options: matching.DefaultOptions}
+	case "osrm":
+		matcher = osrm.New(config.MATCH.Url)
+	case "here_maps":
+		base, errBase := newBase(config.MAPS)
+		if m, ok := base.(Matcher); ok && errBase == nil {
+			matcher = m
+		} else {
+			err = fmt.Errorf("match provider %v requires maps provider here_maps", config.MATCH.Provider)
+		}
+	default:
+		err = fmt.Errorf("invalid match engine %v", config.MATCH.Provider)
+	}
+
+	return matcher, err
+}
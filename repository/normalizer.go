@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"strings"
+
+	"maps.patio.com/addrparse"
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+// StructuredGeocoder is implemented by providers that accept the address split in components
+type StructuredGeocoder interface {
+	GeocodingStructured(parsed *entity.ParsedAddress, language string) (status string, location *entity.Address, err error)
+}
+
+// Normalizer sends the address as written and, when the provider finds
+// nothing, retries with the parsed components, the parser is lossy so its
+// result is only a hint
+type Normalizer struct {
+	Repository
+}
+
+func (n *Normalizer) Geocoding(address string, language string) (string, *entity.Address, error) {
+	statusMaps, result, err := n.Repository.Geocoding(address, language)
+	if err == nil || statusMaps != status.ZERO_RESULTS {
+		return statusMaps, result, err
+	}
+
+	parsed := addrparse.Parse(address)
+	canonical := addrparse.Canonical(parsed)
+	if strings.TrimSpace(canonical) == "" || canonical == strings.TrimSpace(address) {
+		return statusMaps, result, err
+	}
+
+	if structured, ok := n.Repository.(StructuredGeocoder); ok {
+		return structured.GeocodingStructured(parsed, language)
+	}
+	return n.Repository.Geocoding(canonical, language)
+}
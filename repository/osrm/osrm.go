@@ -0,0 +1,115 @@
+package osrm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+type OSRM struct {
+	Url string
+}
+
+type MatchResponse struct {
+	Code        string        `json:"code"`
+	Message     string        `json:"message"`
+	Matchings   []Matching    `json:"matchings"`
+	Tracepoints []*Tracepoint `json:"tracepoints"`
+}
+
+type Matching struct {
+	Distance   float64  `json:"distance"`
+	Duration   float64  `json:"duration"`
+	Confidence float64  `json:"confidence"`
+	Geometry   Geometry `json:"geometry"`
+}
+
+type Geometry struct {
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+type Tracepoint struct {
+	Location      []float64 `json:"location"`
+	MatchingIndex int       `json:"matchings_index"`
+}
+
+func New(uri string) *OSRM {
+	return &OSRM{
+		Url: strings.TrimRight(uri, "/"),
+	}
+}
+
+func (o *OSRM) Match(trace []*entity.TracePoint) (string, *entity.Match, error) {
+	coords := []string{}
+	timestamps := []string{}
+	for _, v := range trace {
+		coords = append(coords, fmt.Sprintf("%f,%f", v.Lng, v.Lat))
+		timestamps = append(timestamps, fmt.Sprint(v.Timestamp))
+	}
+
+	params := url.Values{}
+	params.Add("geometries", "geojson")
+	params.Add("overview", "full")
+	params.Add("gaps", "ignore")
+	params.Add("timestamps", strings.Join(timestamps, ";"))
+
+	var uri string = fmt.Sprintf("%s/match/v1/driving/%s?%s", o.Url, strings.Join(coords, ";"), params.Encode())
+	resp, err := http.Get(uri)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+
+	defer resp.Body.Close()
+	bytes, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return status.FAILED, nil, errRead
+	}
+
+	var response MatchResponse
+	errUnmarshal := json.Unmarshal(bytes, &response)
+	if errUnmarshal != nil {
+		return status.FAILED, nil, errUnmarshal
+	}
+
+	if response.Code != "Ok" || len(response.Matchings) == 0 {
+		if response.Message != "" {
+			return status.ZERO_RESULTS, nil, errors.New(response.Message)
+		}
+		return status.ZERO_RESULTS, nil, errors.New("No match for trace")
+	}
+
+	match := &entity.Match{
+		Polyline: []*entity.Location{},
+		Points:   []*entity.MatchedPoint{},
+	}
+	for _, m := range response.Matchings {
+		match.Distance += m.Distance
+		match.Duration += m.Duration
+		for _, c := range m.Geometry.Coordinates {
+			match.Polyline = append(match.Polyline, &entity.Location{Lat: c[1], Lng: c[0]})
+		}
+	}
+
+	for i, v := range response.Tracepoints {
+		if v == nil || len(v.Location) < 2 || v.MatchingIndex >= len(response.Matchings) {
+			match.Points = append(match.Points, &entity.MatchedPoint{
+				Location: &entity.Location{Lat: trace[i].Lat, Lng: trace[i].Lng},
+			})
+			continue
+		}
+		match.Points = append(match.Points, &entity.MatchedPoint{
+			Location:   &entity.Location{Lat: v.Location[1], Lng: v.Location[0]},
+			Confidence: response.Matchings[v.MatchingIndex].Confidence,
+			Matched:    true,
+		})
+	}
+
+	return status.OK, match, nil
+}
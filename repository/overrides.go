@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"sync"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/overrides"
+	status "maps.patio.com/responses"
+)
+
+// Overrides answers from the locations pinned by ops before asking the provider
+type Overrides struct {
+	Repository
+	Store *overrides.Store
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = map[string]*overrides.Store{}
+)
+
+// OpenOverrides opens the configured store once, every repository and the
+// admin endpoints share it so corrections apply everywhere right away
+func OpenOverrides(config *configuration.Configuration) (*overrides.Store, error) {
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	if store, ok := stores[config.OVERRIDES.Path]; ok {
+		return store, nil
+	}
+	store, err := overrides.Open(config.OVERRIDES.Path)
+	if err != nil {
+		return nil, err
+	}
+	stores[config.OVERRIDES.Path] = store
+	return store, nil
+}
+
+func overrideAddress(override *entity.Override) *entity.Address {
+	address := &entity.Address{
+		Name:    override.Name,
+		Address: override.Address,
+	}
+	if override.Location != nil {
+		location := *override.Location
+		address.Location = &location
+	}
+	return address
+}
+
+func (o *Overrides) Geocoding(address string, language string) (string, *entity.Address, error) {
+	if override, ok := o.Store.Lookup(address); ok {
+		return status.OK, overrideAddress(override), nil
+	}
+	return o.Repository.Geocoding(address, language)
+}
+
+// Search only puts the override on the first page so it is not repeated, it
+// takes the slot of a provider result so the page keeps its size and the next
+// page starts right after the last provider result
+func (o *Overrides) Search(address string, location *entity.Location, limit int, cursor string, language string) (string, []*entity.Address, string, error) {
+	if cursor != "" {
+		return o.Repository.Search(address, location, limit, cursor, language)
+	}
+	override, ok := o.Store.Lookup(address)
+	if !ok {
+		return o.Repository.Search(address, location, limit, cursor, language)
+	}
+	if limit == 1 {
+		return status.OK, []*entity.Address{overrideAddress(override)}, "", nil
+	}
+
+	if limit > 0 {
+		limit--
+	}
+	_, places, next, err := o.Repository.Search(address, location, limit, cursor, language)
+
+	list := []*entity.Address{overrideAddress(override)}
+	if err == nil {
+		list = append(list, places...)
+	}
+	return status.OK, list, next, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"maps.patio.com/entity"
+	"maps.patio.com/pluscode"
+	status "maps.patio.com/responses"
+)
+
+// PlusCodes resolves Open Location Codes locally, short codes are recovered
+// with the provider location of the reference locality that follows them
+type PlusCodes struct {
+	Repository
+}
+
+func (p *PlusCodes) Geocoding(address string, language string) (string, *entity.Address, error) {
+	code, locality, ok := pluscode.Find(address)
+	if !ok {
+		return p.Repository.Geocoding(address, language)
+	}
+
+	if pluscode.IsShort(code) {
+		if locality == "" {
+			return p.Repository.Geocoding(address, language)
+		}
+		statusMaps, reference, err := p.Repository.Geocoding(locality, language)
+		if err != nil {
+			return statusMaps, nil, err
+		}
+		code, err = pluscode.RecoverNearest(code, reference.Location.Lat, reference.Location.Lng)
+		if err != nil {
+			return status.INVALID_DATA, nil, err
+		}
+	}
+
+	area, err := pluscode.ToEntity(code)
+	if err != nil {
+		return status.INVALID_DATA, nil, err
+	}
+
+	return status.OK, &entity.Address{
+		Name:     area.Code,
+		Address:  address,
+		Location: area.Location,
+	}, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 
+	"maps.patio.com/cache"
 	"maps.patio.com/configuration"
 	"maps.patio.com/entity"
 	"maps.patio.com/repository/googlemaps"
@@ -11,26 +12,52 @@ import (
 
 type Repository interface {
 	Provider() (provider string)
-	Geocoding(address string) (status string, location *entity.Address, err error)
-	ReverseGeocoding(location *entity.Location) (status string, address *entity.Address, err error)
-	Search(address string, location *entity.Location) (status string, places []*entity.Address, err error)
-	Distance(origin *entity.Location, destination *entity.Location) (status string, route *entity.Summary, err error)
-	Route(origin *entity.Location, destination *entity.Location) (status string, route *entity.Route, err error)
+	Geocoding(address string, language string) (status string, location *entity.Address, err error)
+	ReverseGeocoding(location *entity.Location, language string) (status string, address *entity.Address, err error)
+	Search(address string, location *entity.Location, limit int, cursor string, language string) (status string, places []*entity.Address, next string, err error)
+	Distance(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (status string, route *entity.Summary, err error)
+	Route(origin *entity.Location, destination *entity.Location, options *entity.RouteOptions, language string) (status string, route *entity.Route, err error)
+	Place(id string, sessionToken string, language string) (status string, place *entity.PlaceDetails, err error)
+	Nearby(location *entity.Location, radius float64, categories []string, language string) (status string, places []*entity.POI, err error)
+	Autocomplete(input string, location *entity.Location, sessionToken string, language string) (status string, suggestions []*entity.Suggestion, err error)
 }
 
 func New(config *configuration.Configuration) (Repository, error) {
+	return NewProvider(config, config.MAPS)
+}
 
-	var repo Repository
-	var err error
-
-	switch config.MAPS.Provider {
+// newBase builds the repository of a provider without decorators, the
+// decorators hide optional capabilities such as Matcher
+func newBase(maps configuration.Maps) (Repository, error) {
+	switch maps.Provider {
 	case "google_maps":
-		repo = googlemaps.New(config.MAPS.ApiKey)
+		return googlemaps.New(maps.ApiKey), nil
 	case "here_maps":
-		repo = heremaps.New(config.MAPS.ApiKey)
+		return heremaps.New(maps.ApiKey), nil
 	default:
-		err = fmt.Errorf("invalid engine %v", config.MAPS.Provider)
+		return nil, fmt.Errorf("invalid engine %v", maps.Provider)
+	}
+}
+
+// NewProvider builds the repository of a provider with the configured decorators
+func NewProvider(config *configuration.Configuration, maps configuration.Maps) (Repository, error) {
+	repo, err := newBase(maps)
+	if err != nil {
+		return nil, err
+	}
+
+	repo = &Normalizer{Repository: repo}
+	if config.CACHE.TTL > 0 {
+		repo = &Cached{Repository: repo, Cache: cache.New(config.CACHE.TTL, config.CACHE.Size)}
+	}
+	repo = &PlusCodes{Repository: repo}
+	repo = &Coordinates{Repository: repo}
+
+	store, err := OpenOverrides(config)
+	if err != nil {
+		return nil, err
 	}
+	repo = &Overrides{Repository: repo, Store: store}
 
-	return repo, err
+	return repo, nil
 }
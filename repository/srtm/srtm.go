@@ -0,0 +1,147 @@
+// Package srtm answers elevations offline from SRTM .hgt tiles, every tile is
+// named after its south west corner (S18W064.hgt) and holds big endian int16
+// samples in rows from north to south, 1201 or 3601 samples per side
+package srtm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"maps.patio.com/entity"
+	status "maps.patio.com/responses"
+)
+
+const void = -32768
+
+var ErrNoData = errors.New("no elevation data for the location")
+
+type tile struct {
+	file *os.File
+	size int
+}
+
+type SRTM struct {
+	Dir   string
+	mu    sync.Mutex
+	tiles map[string]*tile
+}
+
+func New(dir string) *SRTM {
+	return &SRTM{Dir: dir, tiles: map[string]*tile{}}
+}
+
+// TileName returns the file name of the tile containing the location
+func TileName(lat float64, lng float64) string {
+	south := int(math.Floor(lat))
+	west := int(math.Floor(lng))
+	ns, ew := "N", "E"
+	if south < 0 {
+		ns, south = "S", -south
+	}
+	if west < 0 {
+		ew, west = "W", -west
+	}
+	return fmt.Sprintf("%s%02d%s%03d.hgt", ns, south, ew, west)
+}
+
+// open keeps the tiles open, missing tiles are remembered as nil files
+func (s *SRTM) open(name string) (*tile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.tiles[name]; ok {
+		return t, nil
+	}
+
+	t := &tile{}
+	file, err := os.Open(filepath.Join(s.Dir, name))
+	if err == nil {
+		info, errStat := file.Stat()
+		if errStat != nil {
+			file.Close()
+			return nil, errStat
+		}
+		t.size = int(math.Sqrt(float64(info.Size() / 2)))
+		if t.size*t.size*2 != int(info.Size()) || t.size < 2 {
+			file.Close()
+			return nil, fmt.Errorf("invalid tile %v", name)
+		}
+		t.file = file
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	s.tiles[name] = t
+	return t, nil
+}
+
+func (t *tile) sample(row int, col int) (int16, error) {
+	buf := make([]byte, 2)
+	if _, err := t.file.ReadAt(buf, int64(row*t.size+col)*2); err != nil {
+		return 0, err
+	}
+	return int16(binary.BigEndian.Uint16(buf)), nil
+}
+
+// Lookup interpolates the four samples around the location
+func (s *SRTM) Lookup(location *entity.Location) (float64, error) {
+	t, err := s.open(TileName(location.Lat, location.Lng))
+	if err != nil {
+		return 0, err
+	}
+	if t.file == nil {
+		return 0, ErrNoData
+	}
+
+	y := (math.Ceil(location.Lat) - location.Lat) * float64(t.size-1)
+	x := (location.Lng - math.Floor(location.Lng)) * float64(t.size-1)
+	if location.Lat == math.Ceil(location.Lat) {
+		y = float64(t.size - 1)
+	}
+	row, col := int(y), int(x)
+	if row >= t.size-1 {
+		row = t.size - 2
+	}
+	if col >= t.size-1 {
+		col = t.size - 2
+	}
+	dy, dx := y-float64(row), x-float64(col)
+
+	weights := []float64{(1 - dx) * (1 - dy), dx * (1 - dy), (1 - dx) * dy, dx * dy}
+	cells := [][2]int{{row, col}, {row, col + 1}, {row + 1, col}, {row + 1, col + 1}}
+	total, sum := 0.0, 0.0
+	for i, cell := range cells {
+		v, err := t.sample(cell[0], cell[1])
+		if err != nil {
+			return 0, err
+		}
+		if v == void {
+			continue
+		}
+		total += weights[i]
+		sum += weights[i] * float64(v)
+	}
+	if total == 0 {
+		return 0, ErrNoData
+	}
+	return sum / total, nil
+}
+
+func (s *SRTM) Elevation(points []*entity.Location) (string, []float64, error) {
+	list := []float64{}
+	for _, v := range points {
+		elevation, err := s.Lookup(v)
+		if err == ErrNoData {
+			return status.ZERO_RESULTS, nil, err
+		}
+		if err != nil {
+			return status.FAILED, nil, err
+		}
+		list = append(list, elevation)
+	}
+	return status.OK, list, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+	"maps.patio.com/repository/googlemaps"
+	status "maps.patio.com/responses"
+	"maps.patio.com/timezone"
+)
+
+type TimeZoner interface {
+	TimeZone(location *entity.Location, at time.Time) (status string, zone *entity.TimeZone, err error)
+}
+
+// LocalTimeZones answers from the boundary dataset and asks the fallback,
+// when there is one, for the locations outside of it
+type LocalTimeZones struct {
+	Finder   *timezone.Finder
+	Fallback TimeZoner
+}
+
+func (l *LocalTimeZones) TimeZone(location *entity.Location, at time.Time) (string, *entity.TimeZone, error) {
+	id, ok := l.Finder.Lookup(location)
+	if !ok {
+		if l.Fallback != nil {
+			return l.Fallback.TimeZone(location, at)
+		}
+		return status.ZERO_RESULTS, nil, errors.New("no timezone for the location")
+	}
+
+	zone, err := timezone.At(id, at)
+	if err != nil {
+		return status.FAILED, nil, err
+	}
+	return status.OK, zone, nil
+}
+
+// NewTimeZone answers from the dataset at path, or only from the fallback
+// without one, it returns nil when neither is configured
+func NewTimeZone(config *configuration.Configuration) (TimeZoner, error) {
+	var fallback TimeZoner
+
+	switch config.TIMEZONE.Fallback {
+	case "":
+	case "google_maps":
+		if config.MAPS.Provider != "google_maps" {
+			return nil, fmt.Errorf("timezone fallback %v requires maps provider google_maps", config.TIMEZONE.Fallback)
+		}
+		fallback = googlemaps.New(config.MAPS.ApiKey)
+	default:
+		return nil, fmt.Errorf("invalid timezone fallback %v", config.TIMEZONE.Fallback)
+	}
+
+	if config.TIMEZONE.Path == "" {
+		return fallback, nil
+	}
+	finder, err := timezone.Load(config.TIMEZONE.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalTimeZones{Finder: finder, Fallback: fallback}, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"fmt"
+
+	"maps.patio.com/configuration"
+	"maps.patio.com/entity"
+)
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewVehicles validates the configured vehicle profiles and indexes them by name
+func NewVehicles(config *configuration.Configuration) (map[string]*entity.VehicleProfile, error) {
+	vehicles := map[string]*entity.VehicleProfile{}
+	for name, v := range config.VEHICLES {
+		if !contains(entity.VehicleModes, v.Mode) {
+			return nil, fmt.Errorf("vehicle %v: invalid mode %v", name, v.Mode)
+		}
+		for _, goods := range v.HazardousGoods {
+			if !contains(entity.HazardousGoods, goods) {
+				return nil, fmt.Errorf("vehicle %v: invalid hazardous goods %v", name, goods)
+			}
+		}
+
+		profile := &entity.VehicleProfile{
+			Name:           name,
+			Mode:           v.Mode,
+			Height:         v.Height,
+			Width:          v.Width,
+			Length:         v.Length,
+			GrossWeight:    v.GrossWeight,
+			WeightPerAxle:  v.WeightPerAxle,
+			AxleCount:      v.AxleCount,
+			TrailerCount:   v.TrailerCount,
+			HazardousGoods: v.HazardousGoods,
+		}
+		if profile.Restricted() && profile.Mode != entity.VEHICLE_TRUCK {
+			return nil, fmt.Errorf("vehicle %v: dimensions, weights and hazardous goods need the truck mode", name)
+		}
+		vehicles[name] = profile
+	}
+	return vehicles, nil
+}
@@ -3,20 +3,58 @@ package routes
 import (
 	"github.com/gorilla/mux"
 	ctrl "maps.patio.com/controllers"
+	"maps.patio.com/entity"
+	"maps.patio.com/overrides"
 	"maps.patio.com/repository"
+	"maps.patio.com/savedplaces"
 )
 
-func Maps(repo repository.Repository) *mux.Router {
+func Maps(repo repository.Repository, matcher repository.Matcher, maxTracePoints int, store *overrides.Store, places *savedplaces.Store, adminToken string, feedbackToken string, savedPlacesToken string, language string, vehicles map[string]*entity.VehicleProfile, elevator repository.Elevator, timeZoner repository.TimeZoner, consensus *repository.Consensus) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 
 	ctrl.New(repo)
+	ctrl.NewMatcher(matcher, maxTracePoints)
+	ctrl.NewOverrides(store, adminToken, feedbackToken)
+	ctrl.NewSavedPlaces(places, savedPlacesToken)
+	ctrl.NewLanguage(language)
+	ctrl.NewVehicles(vehicles)
+	ctrl.NewElevation(elevator)
+	ctrl.NewTimeZone(timeZoner)
+	ctrl.NewConsensus(consensus)
 
 	router.HandleFunc("/", ctrl.IndexRoute)
 	router.HandleFunc("/geocoding", ctrl.Geocoding).Methods("POST")
+	router.HandleFunc("/geocoding/consensus", ctrl.ConsensusGeocoding).Methods("POST")
 	router.HandleFunc("/reverse-geocoding", ctrl.ReverseGeocoding).Methods("POST")
 	router.HandleFunc("/search", ctrl.Search).Methods("POST")
+	router.HandleFunc("/autocomplete", ctrl.Autocomplete).Methods("POST")
+	router.HandleFunc("/nearby", ctrl.Nearby).Methods("POST")
+	router.HandleFunc("/places/{id}", ctrl.Place).Methods("GET")
 	router.HandleFunc("/distance", ctrl.Distance).Methods("POST")
 	router.HandleFunc("/route", ctrl.Route).Methods("POST")
+	router.HandleFunc("/route/export", ctrl.RouteExport).Methods("GET", "POST")
+	router.HandleFunc("/route/progress", ctrl.RouteProgress).Methods("POST")
+	router.HandleFunc("/route/along", ctrl.RouteAlong).Methods("POST")
+	router.HandleFunc("/match", ctrl.Match).Methods("POST")
+	router.HandleFunc("/vehicles", ctrl.Vehicles).Methods("GET")
+	router.HandleFunc("/elevation", ctrl.Elevation).Methods("POST")
+	router.HandleFunc("/timezone", ctrl.TimeZone).Methods("POST")
+	router.HandleFunc("/pluscode/encode", ctrl.PlusCodeEncode).Methods("POST")
+	router.HandleFunc("/pluscode/decode", ctrl.PlusCodeDecode).Methods("POST")
+	router.HandleFunc("/feedback", ctrl.FeedbackOnly(ctrl.Feedback)).Methods("POST")
+
+	router.HandleFunc("/customers/{customer}/places", ctrl.SavedPlacesOnly(ctrl.ListSavedPlaces)).Methods("GET")
+	router.HandleFunc("/customers/{customer}/places", ctrl.SavedPlacesOnly(ctrl.SaveSavedPlace)).Methods("POST")
+	router.HandleFunc("/customers/{customer}/places/{id}", ctrl.SavedPlacesOnly(ctrl.GetSavedPlace)).Methods("GET")
+	router.HandleFunc("/customers/{customer}/places/{id}", ctrl.SavedPlacesOnly(ctrl.SaveSavedPlace)).Methods("PUT")
+	router.HandleFunc("/customers/{customer}/places/{id}", ctrl.SavedPlacesOnly(ctrl.DeleteSavedPlace)).Methods("DELETE")
+
+	router.HandleFunc("/admin/overrides", ctrl.AdminOnly(ctrl.ListOverrides)).Methods("GET")
+	router.HandleFunc("/admin/overrides", ctrl.AdminOnly(ctrl.SaveOverride)).Methods("POST")
+	router.HandleFunc("/admin/overrides/{id}", ctrl.AdminOnly(ctrl.SaveOverride)).Methods("PUT")
+	router.HandleFunc("/admin/overrides/{id}", ctrl.AdminOnly(ctrl.DeleteOverride)).Methods("DELETE")
+	router.HandleFunc("/admin/feedback", ctrl.AdminOnly(ctrl.ListFeedback)).Methods("GET")
+	router.HandleFunc("/admin/feedback/{id}/{action:approve|reject}", ctrl.AdminOnly(ctrl.ReviewFeedback)).Methods("POST")
 
 	return router
 }
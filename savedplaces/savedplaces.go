@@ -0,0 +1,172 @@
+// Package savedplaces keeps every customer's address book, persisted as a JSON file
+package savedplaces
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"maps.patio.com/addrparse"
+	"maps.patio.com/entity"
+	"maps.patio.com/filestore"
+)
+
+var ErrNotFound = errors.New("not found")
+var ErrConflict = errors.New("the customer already has a place with this label")
+
+// aliases lets customers search their labels in Spanish
+var aliases = map[string]string{
+	entity.PLACE_HOME: "casa hogar mi casa",
+	entity.PLACE_WORK: "trabajo oficina",
+}
+
+type Store struct {
+	mu     sync.RWMutex
+	path   string
+	places map[string]*entity.SavedPlace
+}
+
+// Open loads the store from path, an empty path keeps everything in memory
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:   path,
+		places: map[string]*entity.SavedPlace{},
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	list := []*entity.SavedPlace{}
+	if err := filestore.Read(path, &list); err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		s.places[v.ID] = v
+	}
+	return s, nil
+}
+
+// List returns the customer places, home and work first
+func (s *Store) List(customerID string) []*entity.SavedPlace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []*entity.SavedPlace{}
+	for _, v := range s.places {
+		if v.CustomerID == customerID {
+			list = append(list, v)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if rank(list[i]) != rank(list[j]) {
+			return rank(list[i]) < rank(list[j])
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+func (s *Store) Get(customerID string, id string) (*entity.SavedPlace, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.places[id]
+	if !ok || v.CustomerID != customerID {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+// Save creates or updates the place, a customer has a single home and a single
+// work, updating another place to one of those labels fails
+func (s *Store) Save(place *entity.SavedPlace) (*entity.SavedPlace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	place.Label = strings.ToLower(strings.TrimSpace(place.Label))
+
+	if place.ID != "" {
+		v, ok := s.places[place.ID]
+		if !ok || v.CustomerID != place.CustomerID {
+			return nil, ErrNotFound
+		}
+		if place.Label == entity.PLACE_HOME || place.Label == entity.PLACE_WORK {
+			for _, other := range s.places {
+				if other.ID != place.ID && other.CustomerID == place.CustomerID && other.Label == place.Label {
+					return nil, ErrConflict
+				}
+			}
+		}
+		place.CreatedAt = v.CreatedAt
+	} else if place.Label == entity.PLACE_HOME || place.Label == entity.PLACE_WORK {
+		for _, v := range s.places {
+			if v.CustomerID == place.CustomerID && v.Label == place.Label {
+				place.ID = v.ID
+				place.CreatedAt = v.CreatedAt
+			}
+		}
+	}
+	if place.ID == "" {
+		place.ID = filestore.NewID()
+		place.CreatedAt = now
+	}
+	place.UpdatedAt = now
+
+	s.places[place.ID] = place
+	return place, s.persist()
+}
+
+func (s *Store) Delete(customerID string, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.places[id]
+	if !ok || v.CustomerID != customerID {
+		return ErrNotFound
+	}
+	delete(s.places, id)
+	return s.persist()
+}
+
+// Match returns the customer places whose label, name or address share words with the query
+func (s *Store) Match(customerID string, query string) []*entity.SavedPlace {
+	words := strings.Fields(addrparse.Normalize(query))
+
+	list := []*entity.SavedPlace{}
+	for _, v := range s.List(customerID) {
+		text := " " + addrparse.Normalize(v.Label+" "+aliases[v.Label]+" "+v.Name+" "+v.Address) + " "
+		for _, w := range words {
+			if len(w) > 2 && strings.Contains(text, " "+w) {
+				list = append(list, v)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// persist writes the store to disk, callers hold the lock
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	list := []*entity.SavedPlace{}
+	for _, v := range s.places {
+		list = append(list, v)
+	}
+	return filestore.Write(s.path, list)
+}
+
+func rank(place *entity.SavedPlace) int {
+	switch place.Label {
+	case entity.PLACE_HOME:
+		return 0
+	case entity.PLACE_WORK:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,164 @@
+// Package timezone finds the IANA zone of a location in a timezone boundary
+// dataset, the GeoJSON format published by timezone-boundary-builder with the
+// zone in the "tzid" property, an extract of the regions served is enough
+package timezone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+	_ "time/tzdata"
+
+	"maps.patio.com/entity"
+)
+
+type ring [][2]float64
+
+type polygon []ring
+
+type zone struct {
+	id       string
+	polygons []polygon
+	// south, west, north, east
+	bounds [4]float64
+}
+
+type Finder struct {
+	zones []*zone
+}
+
+type featureCollection struct {
+	Features []struct {
+		Properties struct {
+			TZID string `json:"tzid"`
+		} `json:"properties"`
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// Load reads the dataset at path
+func Load(path string) (*Finder, error) {
+	if path == "" {
+		return nil, errors.New("timezone dataset path is empty")
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(content)
+}
+
+func Parse(content []byte) (*Finder, error) {
+	var collection featureCollection
+	if err := json.Unmarshal(content, &collection); err != nil {
+		return nil, err
+	}
+
+	finder := &Finder{}
+	for _, feature := range collection.Features {
+		if _, err := time.LoadLocation(feature.Properties.TZID); err != nil {
+			return nil, fmt.Errorf("unknown timezone %v", feature.Properties.TZID)
+		}
+
+		var polygons []polygon
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var p polygon
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &p); err != nil {
+				return nil, err
+			}
+			polygons = []polygon{p}
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid geometry %v for %v", feature.Geometry.Type, feature.Properties.TZID)
+		}
+
+		z := &zone{id: feature.Properties.TZID, polygons: polygons, bounds: [4]float64{90, 180, -90, -180}}
+		for _, p := range polygons {
+			if len(p) == 0 {
+				continue
+			}
+			for _, v := range p[0] {
+				if v[1] < z.bounds[0] {
+					z.bounds[0] = v[1]
+				}
+				if v[0] < z.bounds[1] {
+					z.bounds[1] = v[0]
+				}
+				if v[1] > z.bounds[2] {
+					z.bounds[2] = v[1]
+				}
+				if v[0] > z.bounds[3] {
+					z.bounds[3] = v[0]
+				}
+			}
+		}
+		finder.zones = append(finder.zones, z)
+	}
+	return finder, nil
+}
+
+// contains casts a ray from the point, positions are longitude first
+func (r ring) contains(lat float64, lng float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a[1] > lat) != (b[1] > lat) && lng < (b[0]-a[0])*(lat-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// contains checks the outer ring and then the holes
+func (p polygon) contains(lat float64, lng float64) bool {
+	if len(p) == 0 || !p[0].contains(lat, lng) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.contains(lat, lng) {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns the zone containing the location
+func (f *Finder) Lookup(location *entity.Location) (string, bool) {
+	for _, z := range f.zones {
+		if location.Lat < z.bounds[0] || location.Lng < z.bounds[1] || location.Lat > z.bounds[2] || location.Lng > z.bounds[3] {
+			continue
+		}
+		for _, p := range z.polygons {
+			if p.contains(location.Lat, location.Lng) {
+				return z.id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// At returns the zone offset at the given time
+func At(id string, at time.Time) (*entity.TimeZone, error) {
+	location, err := time.LoadLocation(id)
+	if err != nil {
+		return nil, err
+	}
+	local := at.In(location)
+	abbreviation, offset := local.Zone()
+	return &entity.TimeZone{
+		ID:           id,
+		Abbreviation: abbreviation,
+		Offset:       offset,
+		UTCOffset:    local.Format("-07:00"),
+		DST:          local.IsDST(),
+	}, nil
+}
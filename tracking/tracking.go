@@ -0,0 +1,71 @@
+package tracking
+
+import (
+	"math"
+
+	"maps.patio.com/entity"
+	"maps.patio.com/geometry"
+)
+
+const DefaultThreshold = 50.0
+
+// backtrack is how far in meters a courier may appear to move backwards
+// before we look for the position behind the previous one
+const backtrack = 100.0
+
+// Progress snaps the position onto the route and returns how much of it has been travelled,
+// previous is the last known travelled distance, used to avoid snapping to an earlier part
+// of routes that pass twice over the same street
+func Progress(route *entity.Route, position *entity.Location, previous float64, threshold float64) *entity.Progress {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	cumulative := geometry.Cumulative(route.Polyline)
+	snapped, along, deviation := snap(route.Polyline, cumulative, position, previous-backtrack)
+	if snapped == nil {
+		snapped, along, deviation = geometry.Snap(route.Polyline, cumulative, position)
+	}
+
+	length := 0.0
+	if len(cumulative) > 0 {
+		length = cumulative[len(cumulative)-1]
+	}
+
+	progress := &entity.Progress{
+		Position:  snapped,
+		Travelled: along,
+		Remaining: math.Max(0, length-along),
+		Deviation: deviation,
+		OffRoute:  deviation > threshold,
+	}
+	if length > 0 {
+		progress.Progress = along / length
+		progress.Duration = route.Summary.Duration * progress.Remaining / length
+	}
+	return progress
+}
+
+// PointAlong returns the location at the given distance in meters from the start of the route
+func PointAlong(route *entity.Route, distance float64) *entity.Location {
+	return geometry.Interpolate(route.Polyline, geometry.Cumulative(route.Polyline), distance)
+}
+
+// snap is like geometry.Snap but ignores the segments that end before from
+func snap(points []*entity.Location, cumulative []float64, p *entity.Location, from float64) (*entity.Location, float64, float64) {
+	var best *entity.Location
+	bestAlong := 0.0
+	bestDist := math.MaxFloat64
+	for i := 1; i < len(points); i++ {
+		if cumulative[i] < from {
+			continue
+		}
+		snapped, t, dist := geometry.ProjectSegment(points[i-1], points[i], p)
+		if dist < bestDist {
+			best = snapped
+			bestDist = dist
+			bestAlong = cumulative[i-1] + t*(cumulative[i]-cumulative[i-1])
+		}
+	}
+	return best, bestAlong, bestDist
+}